@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// diffContextLines is how many unchanged lines surround each hunk of
+// changes, matching git's default.
+const diffContextLines = 3
+
+// DiffLineKind classifies one line of a Hunk.
+type DiffLineKind int
+
+const (
+	DiffContext DiffLineKind = iota
+	DiffAdd
+	DiffDel
+)
+
+// DiffLine is one line of a Hunk, tagged with its 1-based position in
+// whichever side(s) it appears on (0 if it doesn't appear on that side).
+type DiffLine struct {
+	Kind    DiffLineKind
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// Hunk is one contiguous region of changes (plus surrounding context) in a
+// unified diff.
+type Hunk struct {
+	Header string
+	Lines  []DiffLine
+}
+
+// FileDiff is the full set of hunks between HEAD's version of Path and its
+// current working-tree contents.
+type FileDiff struct {
+	Path  string
+	Hunks []Hunk
+}
+
+type diffCacheKey struct{ path, headSha string }
+
+var (
+	diffCacheMu sync.Mutex
+	diffCache   = map[diffCacheKey]*FileDiff{}
+)
+
+// ComputeDiff returns the unified diff between the index's version of path
+// (i.e. what `git diff --cached` would show as HEAD's side, but reflecting
+// any hunks already staged via stageFocusedHunk) and path's current contents
+// on disk, computed with Myers diff. Diffing against the index rather than
+// HEAD means a staged hunk drops out of the view the moment it's staged,
+// instead of reappearing unchanged because the working tree never moved.
+// Results are cached by (path, HEAD sha) so repeated tab switches are
+// instant; the cache entry for path is dropped by invalidateDiffCache after
+// a hunk is staged or reverted.
+func ComputeDiff(path string) (*FileDiff, error) {
+	sha, err := headSha()
+	if err != nil {
+		return nil, err
+	}
+	key := diffCacheKey{path, sha}
+
+	diffCacheMu.Lock()
+	if fd, ok := diffCache[key]; ok {
+		diffCacheMu.Unlock()
+		return fd, nil
+	}
+	diffCacheMu.Unlock()
+
+	oldContent, _ := gitShowIndex(path) // untracked file: treat as empty
+	newContent := ""
+	if b, err := os.ReadFile(path); err == nil {
+		newContent = string(b)
+	}
+
+	flat := myersDiff(splitLines(oldContent), splitLines(newContent))
+	fd := &FileDiff{Path: path, Hunks: groupHunks(flat, diffContextLines)}
+
+	diffCacheMu.Lock()
+	diffCache[key] = fd
+	diffCacheMu.Unlock()
+	return fd, nil
+}
+
+func invalidateDiffCache(path string) {
+	diffCacheMu.Lock()
+	defer diffCacheMu.Unlock()
+	for k := range diffCache {
+		if k.path == path {
+			delete(diffCache, k)
+		}
+	}
+}
+
+func headSha() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func gitShowIndex(path string) (string, error) {
+	out, err := exec.Command("git", "show", ":"+path).Output()
+	if err != nil {
+		return "", fmt.Errorf("git show :%s: %w", path, err)
+	}
+	return string(out), nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// myersDiff computes the shortest edit script turning a into b (the Myers
+// O(ND) algorithm) and returns it as a flat list of context/add/del lines.
+func myersDiff(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	v := map[int]int{1: 0}
+	trace := make([]map[int]int, 0, max+1)
+
+	for d := 0; d <= max; d++ {
+		snapshot := make(map[int]int, len(v))
+		for k, val := range v {
+			snapshot[k] = val
+		}
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				return backtrackMyers(a, b, trace, d)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrackMyers(a, b []string, trace []map[int]int, d int) []DiffLine {
+	x, y := len(a), len(b)
+	var lines []DiffLine
+
+	for D := d; D > 0; D-- {
+		v := trace[D]
+		k := x - y
+		var prevK int
+		if k == -D || (k != D && v[k-1] < v[k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			lines = append(lines, DiffLine{Kind: DiffContext, Text: a[x-1], OldLine: x, NewLine: y})
+			x--
+			y--
+		}
+		if x == prevX {
+			lines = append(lines, DiffLine{Kind: DiffAdd, Text: b[y-1], NewLine: y})
+			y--
+		} else {
+			lines = append(lines, DiffLine{Kind: DiffDel, Text: a[x-1], OldLine: x})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		lines = append(lines, DiffLine{Kind: DiffContext, Text: a[x-1], OldLine: x, NewLine: y})
+		x--
+		y--
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// groupHunks collapses long unchanged runs, keeping `context` lines of
+// padding around each group of changes so distant edits land in separate
+// hunks instead of one diff spanning the whole file.
+func groupHunks(lines []DiffLine, context int) []Hunk {
+	var changedIdx []int
+	for i, l := range lines {
+		if l.Kind != DiffContext {
+			changedIdx = append(changedIdx, i)
+		}
+	}
+	if len(changedIdx) == 0 {
+		return nil
+	}
+
+	type span struct{ lo, hi int }
+	var spans []span
+	start := max(changedIdx[0]-context, 0)
+	end := min(changedIdx[0]+context, len(lines)-1)
+	for _, idx := range changedIdx[1:] {
+		lo := max(idx-context, 0)
+		hi := min(idx+context, len(lines)-1)
+		if lo <= end+1 {
+			if hi > end {
+				end = hi
+			}
+			continue
+		}
+		spans = append(spans, span{start, end})
+		start, end = lo, hi
+	}
+	spans = append(spans, span{start, end})
+
+	hunks := make([]Hunk, 0, len(spans))
+	for _, s := range spans {
+		seg := lines[s.lo : s.hi+1]
+		hunks = append(hunks, Hunk{Header: hunkHeader(seg), Lines: seg})
+	}
+	return hunks
+}
+
+func hunkHeader(seg []DiffLine) string {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, l := range seg {
+		switch l.Kind {
+		case DiffContext:
+			if oldStart == 0 {
+				oldStart = l.OldLine
+			}
+			if newStart == 0 {
+				newStart = l.NewLine
+			}
+			oldCount++
+			newCount++
+		case DiffAdd:
+			if newStart == 0 {
+				newStart = l.NewLine
+			}
+			newCount++
+		case DiffDel:
+			if oldStart == 0 {
+				oldStart = l.OldLine
+			}
+			oldCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+}
+
+// diffDisplayLine is one row of a flattened FileDiff: either a hunk header
+// (a fold point) or one of its lines.
+type diffDisplayLine struct {
+	isHeader bool
+	header   string
+	line     DiffLine
+}
+
+func flattenFileDiff(fd *FileDiff) []diffDisplayLine {
+	var out []diffDisplayLine
+	for _, h := range fd.Hunks {
+		out = append(out, diffDisplayLine{isHeader: true, header: h.Header})
+		for _, l := range h.Lines {
+			out = append(out, diffDisplayLine{line: l})
+		}
+	}
+	return out
+}
+
+func hunkAtDisplayIndex(fd *FileDiff, idx int) *Hunk {
+	pos := 0
+	for i := range fd.Hunks {
+		segLen := 1 + len(fd.Hunks[i].Lines)
+		if idx >= pos && idx < pos+segLen {
+			return &fd.Hunks[i]
+		}
+		pos += segLen
+	}
+	return nil
+}
+
+// hunkPatch renders a single Hunk as a standalone unified-diff patch
+// suitable for `git apply`.
+func hunkPatch(path string, h Hunk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n", path)
+	fmt.Fprintf(&b, "+++ b/%s\n", path)
+	b.WriteString(h.Header + "\n")
+	for _, l := range h.Lines {
+		switch l.Kind {
+		case DiffAdd:
+			b.WriteString("+" + l.Text + "\n")
+		case DiffDel:
+			b.WriteString("-" + l.Text + "\n")
+		default:
+			b.WriteString(" " + l.Text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// applyHunk writes h as a standalone patch and runs `git apply <extraArgs>`
+// against it, e.g. extraArgs=["--cached"] to stage or ["-R"] to revert.
+func applyHunk(path string, h Hunk, extraArgs ...string) error {
+	f, err := os.CreateTemp("", "biview-hunk-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(hunkPatch(path, h)); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	args := append([]string{"apply"}, extraArgs...)
+	args = append(args, f.Name())
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git apply: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
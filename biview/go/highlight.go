@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	hlKeyword = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+	hlString  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	hlComment = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true)
+	hlNumber  = lipgloss.NewStyle().Foreground(lipgloss.Color("215"))
+	hlHeading = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	hlMatch   = lipgloss.NewStyle().Reverse(true)
+)
+
+var goKeywords = map[string]bool{
+	"package": true, "import": true, "func": true, "return": true, "if": true, "else": true,
+	"for": true, "range": true, "var": true, "const": true, "type": true, "struct": true,
+	"interface": true, "map": true, "chan": true, "go": true, "defer": true, "select": true,
+	"switch": true, "case": true, "default": true, "break": true, "continue": true, "nil": true,
+	"true": true, "false": true, "error": true,
+}
+
+// highlightLine renders one line of source with lightweight syntax
+// coloring inferred from the file's extension. Extensions without a
+// tokenizer render unstyled. query, if non-empty, reverse-highlights every
+// occurrence on top of the syntax coloring.
+func highlightLine(file, line, query string) string {
+	var styled string
+	switch {
+	case strings.HasSuffix(file, ".go"):
+		styled = highlightGoLine(line)
+	case strings.HasSuffix(file, ".md"):
+		styled = highlightMarkdownLine(line)
+	default:
+		styled = line
+	}
+	if query == "" || !strings.Contains(line, query) {
+		return styled
+	}
+	// The syntax highlighters above only ever add ANSI wrapping around
+	// whole tokens, so it's safe to re-run the match highlight over the
+	// plain line and prefer it when a match exists.
+	return highlightMatches(line, query)
+}
+
+func highlightMatches(line, query string) string {
+	var out strings.Builder
+	rest := line
+	for {
+		idx := strings.Index(rest, query)
+		if idx < 0 {
+			out.WriteString(rest)
+			break
+		}
+		out.WriteString(rest[:idx])
+		out.WriteString(hlMatch.Render(rest[idx : idx+len(query)]))
+		rest = rest[idx+len(query):]
+	}
+	return out.String()
+}
+
+func highlightGoLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "//") {
+		return hlComment.Render(line)
+	}
+	var out strings.Builder
+	var tok strings.Builder
+	inString := false
+	flush := func() {
+		if tok.Len() == 0 {
+			return
+		}
+		w := tok.String()
+		switch {
+		case goKeywords[w]:
+			out.WriteString(hlKeyword.Render(w))
+		case isNumberToken(w):
+			out.WriteString(hlNumber.Render(w))
+		default:
+			out.WriteString(w)
+		}
+		tok.Reset()
+	}
+	for _, r := range line {
+		if inString {
+			tok.WriteRune(r)
+			if r == '"' {
+				out.WriteString(hlString.Render(tok.String()))
+				tok.Reset()
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case r == '"':
+			flush()
+			tok.WriteRune(r)
+			inString = true
+		case isWordRune(r):
+			tok.WriteRune(r)
+		default:
+			flush()
+			out.WriteRune(r)
+		}
+	}
+	if inString {
+		out.WriteString(hlString.Render(tok.String()))
+	} else {
+		flush()
+	}
+	return out.String()
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func isNumberToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func highlightMarkdownLine(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return hlHeading.Render(line)
+	}
+	return line
+}
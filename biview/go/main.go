@@ -1,8 +1,14 @@
-// Biview (simplified, code-grounded): split TUI with per-(leftTab,rightTab) cursors,
-// Vim-like left hand (a/w/s/d) for LEFT pane and right hand (i/j/k/l) for RIGHT pane.
-// Added: always-on-top status bar; modal summary toggle (h);
-// right-side hotkeys o,p,u,m (u/o = toggle mark types, p = next mark, m = clear marks);
-// Tab = cycle tabs within focused pane; Shift+Tab = switch focus; '1' same as Tab; '2' same as Shift+Tab.
+// Biview (simplified, code-grounded): a tree of resizable split panes, each
+// leaf showing one of Files/Search/Code/Diff/Summary. Ctrl+w s/v splits the
+// focused pane, Ctrl+w c closes it, Ctrl+w h/j/k/l moves focus by screen
+// direction, and </>/+/- resize the focused pane against its sibling.
+// Tab/Shift+Tab cycle the focused leaf's content kind; a/d cycle between
+// the two list kinds; w/s move a list selection; i/j/k/l scroll a code/diff
+// view (Ctrl+d/Ctrl+u jump a half page); o/u/p/m manage line marks on the
+// selected file; / opens a search prompt over the focused code/diff view,
+// and n/N jump between its matches. The Diff tab computes a real unified
+// diff against HEAD (see differ.go); = stages the hunk under the cursor
+// and ! reverts it, making it a lightweight `git add -p`.
 //
 // go get github.com/charmbracelet/bubbletea github.com/charmbracelet/lipgloss
 package main
@@ -17,53 +23,43 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-type focus int
-
-const (
-	fLeft focus = iota
-	fRight
-)
-
-// Per-(leftTab,rightTab) cursor state
+// ComboState is per-pane cursor/selection/viewport state, keyed by
+// PaneNode.ID so it survives splits, closes, and focus swaps.
 type ComboState struct {
-	L  int // left list index
-	Rx int // right cursor x (for future use)
-	Ry int // right cursor y (line)
+	L  int // list index (FileList/SearchList panes)
+	Rx int // LeftCol: horizontal scroll offset (CodeView/DiffView panes)
+	Ry int // cursor line (CodeView/DiffView panes); TopLine follows it, see renderCodeWithMarks
 }
 
+const searchMarkRune = '/'
+
 type model struct {
 	w, h  int
-	f     focus
 	depth int
 
-	leftTabs  []string // e.g., Files | Search
-	rightTabs []string // e.g., Code | Diff
+	root         *PaneNode
+	focused      int
+	pendingCtrlW bool
+
+	searchMode  bool
+	searchQuery string
 
-	// combo[i][j] corresponds to leftTabs[i], rightTabs[j].
-	combo [][]ComboState
+	selectedFile string
 
 	// example data (code-grounded)
 	files      []string
 	searchHits []string
-	codeByFile map[string][]string // right=Code
-	diffByFile map[string][]string // right=Diff (mock)
+	codeByFile map[string][]string
+
+	diffErr string // set when staging/reverting a hunk via git apply fails
 
 	// marks: per-file line markers (two kinds: 'u' and 'o')
 	marks map[string]map[int]rune
 
-	// UI state
-	showSummary bool
+	panes map[int]*ComboState
 }
 
 func initialModel() model {
-	leftTabs := []string{"Files", "Search"}
-	rightTabs := []string{"Code", "Diff"}
-
-	combo := make([][]ComboState, len(leftTabs))
-	for i := range combo {
-		combo[i] = make([]ComboState, len(rightTabs))
-	}
-
 	files := []string{
 		"cmd/tetrad/main.go",
 		"internal/core/tasks.go",
@@ -134,41 +130,222 @@ func initialModel() model {
 		},
 	}
 
-	diffByFile := map[string][]string{
-		"cmd/tetrad/main.go": {
-			"diff --git a/cmd/tetrad/main.go b/cmd/tetrad/main.go",
-			"@@",
-			"- ctx := context.Background()",
-			"+ ctx, cancel := context.WithCancel(context.Background())",
-			"+ defer cancel()",
-		},
-		"internal/core/exec.go": {
-			"diff --git a/internal/core/exec.go b/internal/core/exec.go",
-			"@@",
-			"- // TODO: handle SIGCHLD to reap children",
-			"+ // NOTE: handle SIGCHLD via signal.NotifyContext",
-		},
-	}
+	root := NewLeafPane(ContentFileList)
+	right := splitPane(root, root.ID, SplitHorizontal)
+	right.Content = ContentCodeView
 
 	return model{
-		f:          fLeft,
-		leftTabs:   leftTabs,
-		rightTabs:  rightTabs,
-		combo:      combo,
+		root:       root,
+		focused:    right.ID,
 		files:      files,
 		searchHits: search,
 		codeByFile: codeByFile,
-		diffByFile: diffByFile,
 		marks:      make(map[string]map[int]rune),
+		panes:      make(map[int]*ComboState),
+		selectedFile: files[0],
 	}
 }
 
 func (m model) Init() tea.Cmd { return nil }
 
-// active tabs (kept simple for this minimal file)
-var activeLeft, activeRight int
+func (m *model) state(id int) *ComboState {
+	if m.panes == nil {
+		m.panes = make(map[int]*ComboState)
+	}
+	cs, ok := m.panes[id]
+	if !ok {
+		cs = &ComboState{}
+		m.panes[id] = cs
+	}
+	return cs
+}
+
+func (m model) approxBodyH() int { return max(m.h-3, 1) }
+
+// viewTextLines returns the plain-text rows a CodeView/DiffView leaf is
+// currently showing, for bounds-checking and search; nil for any other
+// kind. DiffView's rows are its flattened hunk headers and lines, computed
+// (and cached) by the Differ subsystem in differ.go.
+func (m model) viewTextLines(kind ContentKind) []string {
+	switch kind {
+	case ContentCodeView:
+		return m.codeByFile[m.selectedFile]
+	case ContentDiffView:
+		fd, err := ComputeDiff(m.selectedFile)
+		if err != nil {
+			return nil
+		}
+		display := flattenFileDiff(fd)
+		out := make([]string, len(display))
+		for i, d := range display {
+			if d.isHeader {
+				out[i] = d.header
+			} else {
+				out[i] = d.line.Text
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func (m model) maxLineLen(kind ContentKind) int {
+	max := 0
+	for _, l := range m.viewTextLines(kind) {
+		if n := len([]rune(l)); n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// runSearch marks every line of the focused view containing query with
+// searchMarkRune, leaving existing 'u'/'o' marks on those lines untouched.
+func (m *model) runSearch(leaf *PaneNode, query string) {
+	if m.selectedFile == "" || query == "" {
+		return
+	}
+	if _, ok := m.marks[m.selectedFile]; !ok {
+		m.marks[m.selectedFile] = make(map[int]rune)
+	}
+	for i, l := range m.viewTextLines(leaf.Content) {
+		if strings.Contains(l, query) {
+			if _, marked := m.marks[m.selectedFile][i]; !marked {
+				m.marks[m.selectedFile][i] = searchMarkRune
+			}
+		}
+	}
+}
+
+// jumpToSearchMatch moves the focused view's cursor to the next (dir>0) or
+// previous (dir<0) search-marked line, wrapping around.
+func (m *model) jumpToSearchMatch(dir int) {
+	leaf := findPane(m.root, m.focused)
+	if leaf == nil || !isViewKind(leaf.Content) || m.selectedFile == "" {
+		return
+	}
+	cs := m.state(leaf.ID)
+	var lines []int
+	for ln, k := range m.marks[m.selectedFile] {
+		if k == searchMarkRune {
+			lines = append(lines, ln)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sort.Ints(lines)
+	if dir > 0 {
+		for _, ln := range lines {
+			if ln > cs.Ry {
+				cs.Ry = ln
+				return
+			}
+		}
+		cs.Ry = lines[0]
+		return
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] < cs.Ry {
+			cs.Ry = lines[i]
+			return
+		}
+	}
+	cs.Ry = lines[len(lines)-1]
+}
+
+func (m model) listLen(kind ContentKind) int {
+	if kind == ContentSearchList {
+		return len(m.searchHits)
+	}
+	return len(m.files)
+}
 
-func (m model) rightBodyH() int { return max(m.h-3, 1) } // 1 status + 1 tabs + 1 spare
+func (m *model) selectedFileFor(cs *ComboState, kind ContentKind) string {
+	switch kind {
+	case ContentFileList:
+		if len(m.files) == 0 {
+			return ""
+		}
+		return m.files[clamp(cs.L, 0, len(m.files)-1)]
+	case ContentSearchList:
+		if len(m.searchHits) == 0 {
+			return ""
+		}
+		h := m.searchHits[clamp(cs.L, 0, len(m.searchHits)-1)]
+		return strings.SplitN(h, ":", 2)[0]
+	}
+	return ""
+}
+
+func (m *model) syncSelectedFile(leaf *PaneNode) {
+	if !isListKind(leaf.Content) {
+		return
+	}
+	cs := m.state(leaf.ID)
+	m.selectedFile = m.selectedFileFor(cs, leaf.Content)
+}
+
+func (m *model) moveFocus(dir string) {
+	rects := layoutRects(m.root, 0, 0, m.w, m.approxBodyH()+1)
+	var cur paneRect
+	found := false
+	for _, r := range rects {
+		if r.id == m.focused {
+			cur, found = r, true
+		}
+	}
+	if !found {
+		return
+	}
+	cx, cy := cur.x+cur.w/2, cur.y+cur.h/2
+	best := m.focused
+	bestDist := -1
+	for _, r := range rects {
+		if r.id == cur.id {
+			continue
+		}
+		rx, ry := r.x+r.w/2, r.y+r.h/2
+		ok := false
+		switch dir {
+		case "h":
+			ok = rx < cx
+		case "l":
+			ok = rx > cx
+		case "k":
+			ok = ry < cy
+		case "j":
+			ok = ry > cy
+		}
+		if !ok {
+			continue
+		}
+		d := absInt(rx-cx) + absInt(ry-cy)
+		if bestDist == -1 || d < bestDist {
+			bestDist = d
+			best = r.id
+		}
+	}
+	m.focused = best
+}
+
+func (m *model) cycleFocusedKind(delta int) {
+	leaf := findPane(m.root, m.focused)
+	if leaf == nil {
+		return
+	}
+	n := int(contentKindCount)
+	k := ((int(leaf.Content)+delta)%n + n) % n
+	leaf.Content = ContentKind(k)
+	m.syncSelectedFile(leaf)
+}
+
+func cycleListKind(k ContentKind) ContentKind {
+	if k == ContentFileList {
+		return ContentSearchList
+	}
+	return ContentFileList
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch ev := msg.(type) {
@@ -177,10 +354,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch ev.String() {
+		key := ev.String()
+
+		if m.pendingCtrlW {
+			m.pendingCtrlW = false
+			switch key {
+			case "s":
+				m.focused = splitPane(m.root, m.focused, SplitVertical).ID
+			case "v":
+				m.focused = splitPane(m.root, m.focused, SplitHorizontal).ID
+			case "c":
+				m.focused = closePane(m.root, m.focused)
+			case "h", "j", "k", "l":
+				m.moveFocus(key)
+			}
+			return m, nil
+		}
+
+		if m.searchMode {
+			switch ev.Type {
+			case tea.KeyEnter:
+				m.searchMode = false
+				m.depth--
+				if leaf := findPane(m.root, m.focused); leaf != nil {
+					m.runSearch(leaf, m.searchQuery)
+				}
+			case tea.KeyEsc:
+				m.searchMode = false
+				m.depth--
+				m.searchQuery = ""
+			case tea.KeyBackspace:
+				if n := len(m.searchQuery); n > 0 {
+					m.searchQuery = m.searchQuery[:n-1]
+				}
+			case tea.KeyRunes:
+				m.searchQuery += string(ev.Runes)
+			}
+			return m, nil
+		}
+
+		switch key {
 		// global
 		case "ctrl+c":
 			return m, tea.Quit
+		case "ctrl+w":
+			m.pendingCtrlW = true
 		case "e":
 			m.depth++
 		case "q":
@@ -188,50 +406,88 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 			m.depth--
-		case "h":
-			m.showSummary = !m.showSummary
 
-		// focus/tab management
+		// focus/content management
 		case "tab", "1":
-			// cycle tabs within focused pane
-			if m.f == fLeft {
-				activeLeft = cycle(activeLeft, +1, len(m.leftTabs))
-			} else {
-				activeRight = cycle(activeRight, +1, len(m.rightTabs))
-			}
+			m.cycleFocusedKind(+1)
 		case "shift+tab", "2":
-			// switch focus between panes
-			if m.f == fLeft {
-				m.f = fRight
-			} else {
-				m.f = fLeft
+			m.cycleFocusedKind(-1)
+
+		// divider resize
+		case "<", "-":
+			resizePane(m.root, m.focused, -0.05)
+		case ">", "+":
+			resizePane(m.root, m.focused, 0.05)
+
+		// list navigation (a,w,s,d)
+		case "a", "d":
+			if leaf := findPane(m.root, m.focused); leaf != nil && isListKind(leaf.Content) {
+				leaf.Content = cycleListKind(leaf.Content)
+				m.syncSelectedFile(leaf)
 			}
-
-		// LEFT pane navigation (a,w,s,d)
-		case "a":
-			activeLeft = cycle(activeLeft, -1, len(m.leftTabs))
-		case "d":
-			activeLeft = cycle(activeLeft, +1, len(m.leftTabs))
 		case "w":
-			cs := &m.combo[activeLeft][activeRight]
-			cs.L = clamp(cs.L-1, 0, m.leftListLen(activeLeft)-1)
+			if leaf := findPane(m.root, m.focused); leaf != nil && isListKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.L = clamp(cs.L-1, 0, m.listLen(leaf.Content)-1)
+				m.syncSelectedFile(leaf)
+			}
 		case "s":
-			cs := &m.combo[activeLeft][activeRight]
-			cs.L = clamp(cs.L+1, 0, m.leftListLen(activeLeft)-1)
+			if leaf := findPane(m.root, m.focused); leaf != nil && isListKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.L = clamp(cs.L+1, 0, m.listLen(leaf.Content)-1)
+				m.syncSelectedFile(leaf)
+			}
 
-		// RIGHT pane navigation (i/j/k/l) and hotkeys (o,p,u,m)
+		// code/diff view navigation (i/j/k/l), half-page jumps, and
+		// hotkeys (o,p,u,m,/,n,N)
 		case "i": // up
-			cs := &m.combo[activeLeft][activeRight]
-			cs.Ry = clamp(cs.Ry-1, 0, m.rightBodyH()-1)
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Ry = clamp(cs.Ry-1, 0, len(m.viewTextLines(leaf.Content))-1)
+			}
 		case "k": // down
-			cs := &m.combo[activeLeft][activeRight]
-			cs.Ry = clamp(cs.Ry+1, 0, m.rightBodyH()-1)
-		case "j": // left
-			cs := &m.combo[activeLeft][activeRight]
-			cs.Rx = clamp(cs.Rx-1, 0, 3)
-		case "l": // right
-			cs := &m.combo[activeLeft][activeRight]
-			cs.Rx = clamp(cs.Rx+1, 0, 3)
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Ry = clamp(cs.Ry+1, 0, len(m.viewTextLines(leaf.Content))-1)
+			}
+		case "ctrl+u": // half page up
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Ry = clamp(cs.Ry-m.approxBodyH()/2, 0, len(m.viewTextLines(leaf.Content))-1)
+			}
+		case "ctrl+d": // half page down
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Ry = clamp(cs.Ry+m.approxBodyH()/2, 0, len(m.viewTextLines(leaf.Content))-1)
+			}
+		case "/":
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				m.searchMode = true
+				m.searchQuery = ""
+				m.depth++
+			}
+		case "n":
+			m.jumpToSearchMatch(+1)
+		case "N":
+			m.jumpToSearchMatch(-1)
+		case "=": // stage focused hunk
+			if leaf := findPane(m.root, m.focused); leaf != nil && leaf.Content == ContentDiffView {
+				m.stageFocusedHunk(leaf)
+			}
+		case "!": // revert focused hunk
+			if leaf := findPane(m.root, m.focused); leaf != nil && leaf.Content == ContentDiffView {
+				m.revertFocusedHunk(leaf)
+			}
+		case "j": // scroll left
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Rx = clamp(cs.Rx-1, 0, m.maxLineLen(leaf.Content))
+			}
+		case "l": // scroll right
+			if leaf := findPane(m.root, m.focused); leaf != nil && isViewKind(leaf.Content) {
+				cs := m.state(leaf.ID)
+				cs.Rx = clamp(cs.Rx+1, 0, m.maxLineLen(leaf.Content))
+			}
 
 		case "u": // toggle mark type 'u' at current line
 			m.toggleMarkAtCurrent('u')
@@ -240,8 +496,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "p": // jump to next mark
 			m.jumpToNextMark()
 		case "m": // clear marks for current file
-			file := m.currentSelectedFile()
-			delete(m.marks, file)
+			delete(m.marks, m.selectedFile)
 		}
 	}
 	return m, nil
@@ -253,6 +508,8 @@ var (
 	tabActiveS   = lipgloss.NewStyle().Bold(true).Underline(true).Padding(0, 1)
 	tabInactiveS = lipgloss.NewStyle().Padding(0, 1).Faint(true)
 	sepStyle     = lipgloss.NewStyle().SetString("│")
+	diffAddStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	diffDelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
 )
 
 // ----- View -----
@@ -260,116 +517,119 @@ func (m model) View() string {
 	if m.w == 0 || m.h == 0 {
 		return "initializing"
 	}
-	innerW := max(m.w, 20)
-	innerH := max(m.h, 10)
-
-	leftW := max(innerW/3, 24)
-	rightW := innerW - leftW - 1
-	bodyH := innerH - 2 // 1 status + 1 tabs row included in panes
-
-	status := m.viewStatus(innerW)
-	left := m.viewLeft(leftW, bodyH)
-	right := m.viewRight(rightW, bodyH)
-
-	row := lipgloss.JoinHorizontal(lipgloss.Top, left, sepStyle.String(), right)
-	return status + "\n" + row
+	status := m.viewStatus(m.w)
+	body := m.renderPane(m.root, m.w, m.approxBodyH()+1)
+	return status + "\n" + body
 }
 
 func (m model) viewStatus(w int) string {
-	file := m.currentSelectedFile()
-	cs := m.combo[activeLeft][activeRight]
-	leftState := fmt.Sprintf("L:%s[%d]", m.leftTabs[activeLeft], cs.L)
-	rightState := fmt.Sprintf("R:%s line=%d file=%s", m.rightTabs[activeRight], cs.Ry+1, file)
-	focus := map[focus]string{fLeft: "LEFT", fRight: "RIGHT"}[m.f]
-	summary := "off"
-	if m.showSummary {
-		summary = "on"
-	}
-	text := fmt.Sprintf("focus=%s depth=%d summary=%s | %s | %s", focus, m.depth, summary, leftState, rightState)
+	leaf := findPane(m.root, m.focused)
+	kind := "?"
+	if leaf != nil {
+		kind = contentKindNames[leaf.Content]
+	}
+	text := fmt.Sprintf("pane=%d(%s) depth=%d file=%s", m.focused, kind, m.depth, m.selectedFile)
 	if len(text) > w {
 		text = text[:max(0, w)]
 	}
 	return accent.Render(text)
 }
 
-func (m model) viewLeft(w, bodyH int) string {
-	tabs := renderTabs(m.leftTabs, activeLeft, m.f == fLeft, "LEFT")
-	items := m.leftList(activeLeft)
-	cs := m.combo[activeLeft][activeRight]
-
-	// clamp selection to available items
-	if n := len(items); n > 0 {
-		cs.L = clamp(cs.L, 0, n-1)
-		m.combo[activeLeft][activeRight] = cs
+func (m model) renderPane(n *PaneNode, w, h int) string {
+	if n.Split == SplitNone {
+		return m.renderLeaf(n, w, h)
 	}
-
-	lines := make([]string, 0, min(bodyH-1, len(items)))
-	for i := 0; i < min(bodyH-1, len(items)); i++ {
-		prefix := "  "
-		text := items[i]
-		if i == cs.L && m.f == fLeft {
-			lines = append(lines, accent.Copy().Bold(true).Render("> "+text))
-		} else {
-			lines = append(lines, prefix+text)
+	if n.Split == SplitHorizontal {
+		sizes := splitSizes(w, n.Children)
+		parts := make([]string, 0, len(n.Children)*2-1)
+		for i, c := range n.Children {
+			if i > 0 {
+				parts = append(parts, sepStyle.String())
+			}
+			parts = append(parts, m.renderPane(c, sizes[i], h))
 		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
 	}
-
-	body := strings.Join(lines, "\n")
-	return tabs + "\n" + body
+	sizes := splitSizes(h, n.Children)
+	parts := make([]string, len(n.Children))
+	for i, c := range n.Children {
+		parts[i] = m.renderPane(c, w, sizes[i])
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
-func (m model) viewRight(w, bodyH int) string {
-	tabs := renderTabs(m.rightTabs, activeRight, m.f == fRight, "RIGHT")
-	cs := m.combo[activeLeft][activeRight]
+func (m model) renderLeaf(n *PaneNode, w, h int) string {
+	focused := n.ID == m.focused
+	title := contentKindNames[n.Content]
+	var header string
+	if focused {
+		header = accent.Render(title + " *")
+	} else {
+		header = tabInactiveS.Render(title)
+	}
+	if focused && m.searchMode {
+		header += " /" + m.searchQuery
+	}
+	cs := m.state(n.ID)
+	bodyH := max(h-1, 1)
 
 	var body string
-	if m.showSummary {
-		body = m.renderSummary(bodyH - 1)
-	} else {
-		switch m.rightTabs[activeRight] {
-		case "Code":
-			file := m.currentSelectedFile()
-			lines := m.codeByFile[file]
-			body = m.renderCodeWithMarks(file, lines, cs.Ry, bodyH-1)
-		case "Diff":
-			file := m.currentSelectedFile()
-			lines := m.diffByFile[file]
-			body = m.renderCodeWithMarks(file, lines, cs.Ry, bodyH-1)
-		default:
-			body = "(no mode)"
+	switch n.Content {
+	case ContentFileList:
+		body = m.renderList(m.files, cs.L, focused, bodyH)
+	case ContentSearchList:
+		body = m.renderList(m.searchHits, cs.L, focused, bodyH)
+	case ContentCodeView:
+		body = m.renderCodeWithMarks(m.selectedFile, m.codeByFile[m.selectedFile], cs.Ry, cs.Rx, bodyH)
+	case ContentDiffView:
+		body = m.renderDiffView(m.selectedFile, cs.Ry, cs.Rx, bodyH)
+	case ContentSummary:
+		body = m.renderSummary(bodyH)
+	}
+	return header + "\n" + body
+}
+
+func (m model) renderList(items []string, sel int, focused bool, bodyH int) string {
+	n := min(bodyH, len(items))
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if i == sel && focused {
+			lines = append(lines, accent.Copy().Bold(true).Render("> "+items[i]))
+		} else {
+			lines = append(lines, "  "+items[i])
 		}
 	}
-	return tabs + "\n" + body
+	return strings.Join(lines, "\n")
 }
 
-// ----- right-pane mark helpers -----
+// ----- mark helpers -----
 func (m *model) toggleMarkAtCurrent(kind rune) {
-	file := m.currentSelectedFile()
-	if file == "" {
+	leaf := findPane(m.root, m.focused)
+	if leaf == nil || !isViewKind(leaf.Content) || m.selectedFile == "" {
 		return
 	}
-	cs := m.combo[activeLeft][activeRight]
-	if _, ok := m.marks[file]; !ok {
-		m.marks[file] = make(map[int]rune)
+	cs := m.state(leaf.ID)
+	if _, ok := m.marks[m.selectedFile]; !ok {
+		m.marks[m.selectedFile] = make(map[int]rune)
 	}
-	if existing, ok := m.marks[file][cs.Ry]; ok && existing == kind {
-		delete(m.marks[file], cs.Ry) // toggle off
+	if existing, ok := m.marks[m.selectedFile][cs.Ry]; ok && existing == kind {
+		delete(m.marks[m.selectedFile], cs.Ry) // toggle off
 	} else {
-		m.marks[file][cs.Ry] = kind
+		m.marks[m.selectedFile][cs.Ry] = kind
 	}
 }
 
 func (m *model) jumpToNextMark() {
-	file := m.currentSelectedFile()
-	if file == "" {
+	leaf := findPane(m.root, m.focused)
+	if leaf == nil || !isViewKind(leaf.Content) || m.selectedFile == "" {
 		return
 	}
-	cs := &m.combo[activeLeft][activeRight]
-	if len(m.marks[file]) == 0 {
+	cs := m.state(leaf.ID)
+	if len(m.marks[m.selectedFile]) == 0 {
 		return
 	}
 	var lines []int
-	for ln := range m.marks[file] {
+	for ln := range m.marks[m.selectedFile] {
 		lines = append(lines, ln)
 	}
 	sort.Ints(lines)
@@ -379,67 +639,16 @@ func (m *model) jumpToNextMark() {
 			return
 		}
 	}
-	// wrap to first
-	cs.Ry = lines[0]
-}
-
-// ----- data helpers -----
-func (m model) leftList(which int) []string {
-	switch m.leftTabs[which] {
-	case "Files":
-		return m.files
-	case "Search":
-		return m.searchHits
-	default:
-		return nil
-	}
-}
-func (m model) leftListLen(which int) int { return len(m.leftList(which)) }
-
-func (m model) currentSelectedFile() string {
-	cs := m.combo[activeLeft][activeRight]
-	switch m.leftTabs[activeLeft] {
-	case "Files":
-		if len(m.files) == 0 {
-			return ""
-		}
-		return m.files[clamp(cs.L, 0, len(m.files)-1)]
-	case "Search":
-		if len(m.searchHits) == 0 {
-			return ""
-		}
-		h := m.searchHits[clamp(cs.L, 0, len(m.searchHits)-1)]
-		parts := strings.SplitN(h, ":", 2)
-		return parts[0]
-	default:
-		return ""
-	}
+	cs.Ry = lines[0] // wrap to first
 }
 
 // ----- presentation helpers -----
-func renderTabs(names []string, active int, focused bool, label string) string {
-	var parts []string
-	lbl := label
-	if focused {
-		lbl = accent.Render(label)
-	}
-	parts = append(parts, lbl+" |")
-	for i, n := range names {
-		if i == active {
-			parts = append(parts, tabActiveS.Render(n))
-		} else {
-			parts = append(parts, tabInactiveS.Render(n))
-		}
-	}
-	return strings.Join(parts, " ")
-}
-
 func (m model) renderSummary(height int) string {
-	file := m.currentSelectedFile()
+	file := m.selectedFile
 	var out []string
 	out = append(out, "Summary")
 	out = append(out, "———")
-	out = append(out, fmt.Sprintf("Left:%s Right:%s Depth:%d", m.leftTabs[activeLeft], m.rightTabs[activeRight], m.depth))
+	out = append(out, fmt.Sprintf("Focused pane:%d Depth:%d", m.focused, m.depth))
 	out = append(out, fmt.Sprintf("File: %s", file))
 	if mk, ok := m.marks[file]; ok && len(mk) > 0 {
 		var lines []int
@@ -459,7 +668,94 @@ func (m model) renderSummary(height int) string {
 	return strings.Join(out, "\n")
 }
 
-func (m model) renderCodeWithMarks(file string, lines []string, cursorY, height int) string {
+// renderDiffView renders path's real diff against HEAD (see differ.go),
+// with hunk headers as fold points and +/-/context lines styled distinctly.
+func (m model) renderDiffView(path string, cursorY, leftCol, height int) string {
+	if path == "" {
+		return "(no file selected)"
+	}
+	fd, err := ComputeDiff(path)
+	if err != nil {
+		return "(diff error: " + err.Error() + ")"
+	}
+	display := flattenFileDiff(fd)
+	if len(display) == 0 {
+		return "(no changes against HEAD)"
+	}
+	start := clamp(cursorY-(height/2), 0, max(0, len(display)-height))
+	end := min(len(display), start+height)
+	var out []string
+	for i := start; i < end; i++ {
+		d := display[i]
+		var row string
+		if d.isHeader {
+			row = hlHeading.Render(d.header)
+		} else {
+			text := []rune(d.line.Text)
+			if leftCol < len(text) {
+				text = text[leftCol:]
+			} else {
+				text = nil
+			}
+			switch d.line.Kind {
+			case DiffAdd:
+				row = diffAddStyle.Render("+ " + string(text))
+			case DiffDel:
+				row = diffDelStyle.Render("- " + string(text))
+			default:
+				row = "  " + string(text)
+			}
+		}
+		if i == cursorY {
+			row = accent.Copy().Bold(true).Render(row)
+		}
+		out = append(out, row)
+	}
+	if m.diffErr != "" {
+		out = append(out, faint.Render("git apply: "+m.diffErr))
+	}
+	return strings.Join(out, "\n")
+}
+
+// stageFocusedHunk stages the hunk at the focused DiffView pane's cursor
+// via `git apply --cached` (turning = into a lightweight `git add -p`).
+func (m *model) stageFocusedHunk(leaf *PaneNode) {
+	m.applyFocusedHunk(leaf, "--cached")
+}
+
+// revertFocusedHunk discards the hunk at the focused DiffView pane's
+// cursor from the working tree via `git apply -R`.
+func (m *model) revertFocusedHunk(leaf *PaneNode) {
+	m.applyFocusedHunk(leaf, "-R")
+}
+
+func (m *model) applyFocusedHunk(leaf *PaneNode, extraArgs ...string) {
+	if m.selectedFile == "" {
+		return
+	}
+	fd, err := ComputeDiff(m.selectedFile)
+	if err != nil {
+		m.diffErr = err.Error()
+		return
+	}
+	cs := m.state(leaf.ID)
+	h := hunkAtDisplayIndex(fd, cs.Ry)
+	if h == nil {
+		return
+	}
+	if err := applyHunk(m.selectedFile, *h, extraArgs...); err != nil {
+		m.diffErr = err.Error()
+		return
+	}
+	m.diffErr = ""
+	invalidateDiffCache(m.selectedFile)
+	cs.Ry = 0
+}
+
+// renderCodeWithMarks renders lines height-tall, viewport centered on
+// cursorY and scrolled leftCol runes in from the start of each line, with
+// marks in the gutter and lightweight syntax/search-match highlighting.
+func (m model) renderCodeWithMarks(file string, lines []string, cursorY, leftCol, height int) string {
 	if len(lines) == 0 {
 		return "(empty)"
 	}
@@ -476,7 +772,13 @@ func (m model) renderCodeWithMarks(file string, lines []string, cursorY, height
 				mark = string(r)
 			}
 		}
-		row := lineNo + "│" + mark + " " + lines[i]
+		visible := []rune(lines[i])
+		if leftCol < len(visible) {
+			visible = visible[leftCol:]
+		} else {
+			visible = nil
+		}
+		row := lineNo + "│" + mark + " " + highlightLine(file, string(visible), m.searchQuery)
 		if i == cursorY {
 			out = append(out, accent.Copy().Bold(true).Render(row))
 		} else {
@@ -501,13 +803,10 @@ func clamp(x, lo, hi int) int {
 }
 func min(a, b int) int { if a < b { return a }; return b }
 func max(a, b int) int { if a > b { return a }; return b }
-func cycle(i, d, n int) int { return (i + d + n) % n }
 
 func main() {
-	activeLeft, activeRight = 0, 0
 	if err := tea.NewProgram(initialModel(), tea.WithAltScreen()).Start(); err != nil {
 		fmt.Println("error:", err)
 		os.Exit(1)
 	}
 }
-
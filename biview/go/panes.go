@@ -0,0 +1,241 @@
+package main
+
+// SplitKind is how a non-leaf PaneNode divides its Children.
+type SplitKind int
+
+const (
+	SplitNone       SplitKind = iota // leaf: holds Content, no Children
+	SplitHorizontal                  // children laid out left-to-right
+	SplitVertical                    // children laid out top-to-bottom
+)
+
+// ContentKind is what a leaf pane displays.
+type ContentKind int
+
+const (
+	ContentFileList ContentKind = iota
+	ContentSearchList
+	ContentCodeView
+	ContentDiffView
+	ContentSummary
+	contentKindCount
+)
+
+var contentKindNames = map[ContentKind]string{
+	ContentFileList:   "Files",
+	ContentSearchList: "Search",
+	ContentCodeView:   "Code",
+	ContentDiffView:   "Diff",
+	ContentSummary:    "Summary",
+}
+
+func isListKind(k ContentKind) bool {
+	return k == ContentFileList || k == ContentSearchList
+}
+
+func isViewKind(k ContentKind) bool {
+	return k == ContentCodeView || k == ContentDiffView
+}
+
+// PaneNode is one node in Biview's split tree: either a leaf (Split ==
+// SplitNone) showing Content, or an internal node dividing Children by
+// Ratio along Split's axis.
+type PaneNode struct {
+	ID       int
+	Split    SplitKind
+	Ratio    float64
+	Children []*PaneNode
+	Content  ContentKind
+	Parent   *PaneNode
+}
+
+var nextPaneID int
+
+func newPaneID() int {
+	nextPaneID++
+	return nextPaneID
+}
+
+// NewLeafPane creates an unattached leaf pane showing content.
+func NewLeafPane(content ContentKind) *PaneNode {
+	return &PaneNode{ID: newPaneID(), Content: content, Ratio: 1}
+}
+
+func findPane(n *PaneNode, id int) *PaneNode {
+	if n == nil {
+		return nil
+	}
+	if n.ID == id {
+		return n
+	}
+	for _, c := range n.Children {
+		if found := findPane(c, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findParentPane(n *PaneNode, id int) *PaneNode {
+	for _, c := range n.Children {
+		if c.ID == id {
+			return n
+		}
+		if p := findParentPane(c, id); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+func firstLeafID(n *PaneNode) int {
+	if n.Split == SplitNone {
+		return n.ID
+	}
+	return firstLeafID(n.Children[0])
+}
+
+// splitPane turns the leaf identified by id into a split node with two
+// equal-ratio children: a clone of its old content, and a fresh leaf of the
+// same kind. It returns the new leaf, which callers focus.
+func splitPane(root *PaneNode, id int, dir SplitKind) *PaneNode {
+	target := findPane(root, id)
+	if target == nil || target.Split != SplitNone {
+		return target
+	}
+	clone := &PaneNode{ID: newPaneID(), Content: target.Content, Ratio: 0.5, Parent: target}
+	sibling := &PaneNode{ID: newPaneID(), Content: target.Content, Ratio: 0.5, Parent: target}
+	target.Split = dir
+	target.Children = []*PaneNode{clone, sibling}
+	return sibling
+}
+
+// closePane removes the leaf identified by id, collapsing its parent into
+// the surviving sibling when only one child remains. It returns the ID of
+// the pane that should now be focused. Closing the last remaining pane is a
+// no-op.
+func closePane(root *PaneNode, id int) int {
+	if root.Split == SplitNone {
+		return root.ID
+	}
+	parent := findParentPane(root, id)
+	if parent == nil {
+		return id
+	}
+	idx := -1
+	for i, c := range parent.Children {
+		if c.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return id
+	}
+	parent.Children = append(parent.Children[:idx], parent.Children[idx+1:]...)
+	if len(parent.Children) == 1 {
+		sibling := parent.Children[0]
+		savedParent := parent.Parent
+		*parent = *sibling
+		parent.Parent = savedParent
+		for _, c := range parent.Children {
+			c.Parent = parent
+		}
+	}
+	return firstLeafID(parent)
+}
+
+// resizePane nudges the ratio of the leaf identified by id against its
+// sibling within a two-child parent, clamped to [0.1, 0.9].
+func resizePane(root *PaneNode, id int, delta float64) {
+	parent := findParentPane(root, id)
+	if parent == nil || len(parent.Children) != 2 {
+		return
+	}
+	leaf := findPane(root, id)
+	var other *PaneNode
+	for _, c := range parent.Children {
+		if c.ID != id {
+			other = c
+		}
+	}
+	if leaf == nil || other == nil {
+		return
+	}
+	nr := clampf(leaf.Ratio+delta, 0.1, 0.9)
+	diff := nr - leaf.Ratio
+	leaf.Ratio = nr
+	other.Ratio -= diff
+}
+
+// splitSizes distributes total pixels among children proportionally to
+// their Ratio, giving the remainder to the last child so sizes always sum
+// to total exactly.
+func splitSizes(total int, children []*PaneNode) []int {
+	sumRatio := 0.0
+	for _, c := range children {
+		sumRatio += c.Ratio
+	}
+	if sumRatio <= 0 {
+		sumRatio = float64(len(children))
+	}
+	sizes := make([]int, len(children))
+	used := 0
+	for i, c := range children {
+		if i == len(children)-1 {
+			sizes[i] = total - used
+			continue
+		}
+		s := int(float64(total) * c.Ratio / sumRatio)
+		sizes[i] = s
+		used += s
+	}
+	return sizes
+}
+
+// paneRect is a leaf's on-screen bounding box, used for directional focus
+// movement (Ctrl+w h/j/k/l).
+type paneRect struct {
+	id         int
+	x, y, w, h int
+}
+
+func layoutRects(n *PaneNode, x, y, w, h int) []paneRect {
+	if n.Split == SplitNone {
+		return []paneRect{{n.ID, x, y, w, h}}
+	}
+	var out []paneRect
+	if n.Split == SplitHorizontal {
+		sizes := splitSizes(w, n.Children)
+		pos := 0
+		for i, c := range n.Children {
+			out = append(out, layoutRects(c, x+pos, y, sizes[i], h)...)
+			pos += sizes[i]
+		}
+	} else {
+		sizes := splitSizes(h, n.Children)
+		pos := 0
+		for i, c := range n.Children {
+			out = append(out, layoutRects(c, x, y+pos, w, sizes[i])...)
+			pos += sizes[i]
+		}
+	}
+	return out
+}
+
+func clampf(x, lo, hi float64) float64 {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
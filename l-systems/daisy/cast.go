@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// CastConfig is the world configuration recorded alongside the RNG seed so a
+// replay can be checked against the build it was captured from.
+type CastConfig struct {
+	GridW         int     `json:"grid_w"`
+	GridH         int     `json:"grid_h"`
+	OrgCount      int     `json:"org_count"`
+	SunPowerStart float64 `json:"sun_power_start"`
+}
+
+func currentCastConfig() CastConfig {
+	return CastConfig{GridW: GridW, GridH: GridH, OrgCount: OrgCount, SunPowerStart: SunPowerStart}
+}
+
+// CastHeader is the first line of a cast file: everything needed to make a
+// replay deterministic given the same binary.
+type CastHeader struct {
+	Seed   int64      `json:"seed"`
+	Config CastConfig `json:"config"`
+}
+
+// CastEvent is one line of a cast file after the header: the NonBlockingRead
+// result captured for a single frame tick.
+type CastEvent struct {
+	Tick     int  `json:"tick"`
+	Byte     byte `json:"byte"`
+	HasInput bool `json:"has_input"`
+}
+
+// Recorder appends one CastEvent per frame to a newline-delimited JSON cast
+// file, preceded by a CastHeader line.
+type Recorder struct {
+	f    *os.File
+	enc  *json.Encoder
+	tick int
+}
+
+// NewRecorder creates path and writes its CastHeader using seed.
+func NewRecorder(path string, seed int64) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(f)
+	if err := enc.Encode(CastHeader{Seed: seed, Config: currentCastConfig()}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Recorder{f: f, enc: enc}, nil
+}
+
+// RecordInput appends the input captured for the current tick and advances
+// to the next one.
+func (rec *Recorder) RecordInput(b byte, ok bool) {
+	rec.enc.Encode(CastEvent{Tick: rec.tick, Byte: b, HasInput: ok})
+	rec.tick++
+}
+
+func (rec *Recorder) Close() error {
+	return rec.f.Close()
+}
+
+// Player replays a cast file's recorded input, one tick at a time.
+type Player struct {
+	Header CastHeader
+	events []CastEvent
+	idx    int
+}
+
+// LoadCast reads a cast file written by Recorder.
+func LoadCast(path string) (*Player, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	p := &Player{}
+	if scanner.Scan() {
+		if err := json.Unmarshal(scanner.Bytes(), &p.Header); err != nil {
+			return nil, err
+		}
+	}
+	for scanner.Scan() {
+		var ev CastEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, err
+		}
+		p.events = append(p.events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Next returns the recorded input for the next tick. more is false once the
+// cast file is exhausted, at which point the replay should stop.
+func (p *Player) Next() (b byte, hasInput bool, more bool) {
+	if p.idx >= len(p.events) {
+		return 0, false, false
+	}
+	ev := p.events[p.idx]
+	p.idx++
+	return ev.Byte, ev.HasInput, true
+}
@@ -1,6 +1,12 @@
 package main
 
-import "strings"
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
 
 func ExpandLSystem(axiom, rule string, steps int) string {
 	result := axiom
@@ -17,3 +23,254 @@ func ExpandLSystem(axiom, rule string, steps int) string {
 	}
 	return result
 }
+
+// Production is one weighted rewrite rule for a symbol in a stochastic,
+// parametric L-system grammar (Prusinkiewicz's ABOP). Body is the
+// replacement string, which may contain parametric tokens such as
+// "F(n*0.9)" or "+(30)" — "n" refers to the predecessor symbol's own
+// parameter and is substituted before the result is appended. Condition,
+// if non-empty, is a guard like "n<5" evaluated against the predecessor's
+// parameter (defaulting to 0 for parameterless symbols); productions
+// whose guard fails are not eligible that step.
+type Production struct {
+	Body      string
+	Weight    float64
+	Condition string
+}
+
+// symToken is one parsed symbol from an L-system string, with its optional
+// parametric argument, e.g. "F(1.5)" or the bare "+".
+type symToken struct {
+	sym      rune
+	param    float64
+	hasParam bool
+}
+
+func (t symToken) String() string {
+	if !t.hasParam {
+		return string(t.sym)
+	}
+	return fmt.Sprintf("%c(%s)", t.sym, trimFloat(t.param))
+}
+
+// tokenize splits an L-system string into symToken, pairing each symbol
+// with a trailing "(...)" argument when present.
+func tokenize(s string) []symToken {
+	rs := []rune(s)
+	var toks []symToken
+	for i := 0; i < len(rs); i++ {
+		t := symToken{sym: rs[i]}
+		if i+1 < len(rs) && rs[i+1] == '(' {
+			j := i + 2
+			for j < len(rs) && rs[j] != ')' {
+				j++
+			}
+			if v, err := strconv.ParseFloat(string(rs[i+2:j]), 64); err == nil {
+				t.param, t.hasParam = v, true
+			}
+			i = j
+		}
+		toks = append(toks, t)
+	}
+	return toks
+}
+
+// trimFloat formats v without trailing zeroes, for embedding a computed
+// parameter back into an expanded L-system string.
+func trimFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// evalParam evaluates a tiny arithmetic expression of "n" and numeric
+// literals against a single "+-*/" operator, e.g. "n*0.9" or "0.6". It's
+// only ever fed the contents of a production's own parenthesized tokens,
+// so this deliberately doesn't handle operator precedence or parens.
+func evalParam(expr string, n float64) float64 {
+	expr = strings.TrimSpace(expr)
+	for _, op := range []byte{'+', '-', '*', '/'} {
+		if idx := strings.IndexByte(expr, op); idx > 0 {
+			left := evalParam(expr[:idx], n)
+			right := evalParam(expr[idx+1:], n)
+			switch op {
+			case '+':
+				return left + right
+			case '-':
+				return left - right
+			case '*':
+				return left * right
+			case '/':
+				if right == 0 {
+					return 0
+				}
+				return left / right
+			}
+		}
+	}
+	if expr == "n" {
+		return n
+	}
+	v, _ := strconv.ParseFloat(expr, 64)
+	return v
+}
+
+// evalCondition evaluates a guard like "n<5" against a predecessor's
+// parameter. An empty guard always passes.
+func evalCondition(cond string, n float64) bool {
+	if cond == "" {
+		return true
+	}
+	for _, op := range []string{"<=", ">=", "==", "<", ">"} {
+		idx := strings.Index(cond, op)
+		if idx < 0 {
+			continue
+		}
+		left := evalParam(cond[:idx], n)
+		right := evalParam(cond[idx+len(op):], n)
+		switch op {
+		case "<=":
+			return left <= right
+		case ">=":
+			return left >= right
+		case "==":
+			return left == right
+		case "<":
+			return left < right
+		case ">":
+			return left > right
+		}
+	}
+	return true
+}
+
+// substituteParams rewrites each "(...)" argument in body by evaluating it
+// against n, the predecessor's parameter. Symbols outside parens pass
+// through unchanged.
+func substituteParams(body string, n float64) string {
+	rs := []rune(body)
+	var out strings.Builder
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '(' {
+			out.WriteRune(rs[i])
+			continue
+		}
+		j := i + 1
+		for j < len(rs) && rs[j] != ')' {
+			j++
+		}
+		out.WriteString("(")
+		out.WriteString(trimFloat(evalParam(string(rs[i+1:j]), n)))
+		out.WriteString(")")
+		i = j
+	}
+	return out.String()
+}
+
+// pickProduction samples a production for a predecessor with parameter n,
+// restricting to productions whose Condition passes n (falling back to the
+// full set if none do), then sampling by Weight using the world RNG so
+// replays stay deterministic.
+func pickProduction(prods []Production, n float64) Production {
+	avail := prods[:0:0]
+	for _, p := range prods {
+		if evalCondition(p.Condition, n) {
+			avail = append(avail, p)
+		}
+	}
+	if len(avail) == 0 {
+		avail = prods
+	}
+	total := 0.0
+	for _, p := range avail {
+		total += p.Weight
+	}
+	if total <= 0 {
+		return avail[0]
+	}
+	r := rand.Float64() * total
+	for _, p := range avail {
+		r -= p.Weight
+		if r <= 0 {
+			return p
+		}
+	}
+	return avail[len(avail)-1]
+}
+
+// ExpandStochastic expands axiom for the given number of steps using a
+// per-symbol grammar of weighted, parametric productions, sampling one
+// production per occurrence each step and substituting its Body's
+// parameters against the predecessor's own parameter. Symbols with no
+// entry in rules pass through unchanged.
+func ExpandStochastic(axiom string, rules map[rune][]Production, steps int) string {
+	result := axiom
+	for i := 0; i < steps; i++ {
+		var next strings.Builder
+		for _, t := range tokenize(result) {
+			prods, ok := rules[t.sym]
+			if !ok || len(prods) == 0 {
+				next.WriteString(t.String())
+				continue
+			}
+			p := pickProduction(prods, t.param)
+			next.WriteString(substituteParams(p.Body, t.param))
+		}
+		result = next.String()
+	}
+	return result
+}
+
+// Point is a grid cell offset, relative to a turtle's origin, produced by
+// Render.
+type Point struct {
+	X, Y int
+}
+
+// Render walks the turtle-graphics instructions in ls (F draws forward,
+// +/- turn, [/] push/pop state) and returns the grid offsets where an F
+// drew a forward segment. A parametric token's argument scales the move
+// ("F(1.5)" draws 1.5 grid units forward) or replaces the turn angle
+// ("+(30)"/"-(30)"); parameterless tokens fall back to a unit move and
+// angle. Shared by the live display and World's footprint stamping so
+// both agree on an organism's shape.
+func Render(ls string, angle float64) []Point {
+	dir := -90.0
+	x, y := 0.0, 0.0
+	var pts []Point
+	stack := []struct {
+		x, y float64
+		d    float64
+	}{}
+	for _, t := range tokenize(ls) {
+		switch t.sym {
+		case 'F':
+			dist := 1.0
+			if t.hasParam {
+				dist = t.param
+			}
+			x += dist * math.Cos(dir*math.Pi/180)
+			y += dist * math.Sin(dir*math.Pi/180)
+			pts = append(pts, Point{int(math.Round(x)), int(math.Round(y))})
+		case '+':
+			turn := angle
+			if t.hasParam {
+				turn = t.param
+			}
+			dir += turn
+		case '-':
+			turn := angle
+			if t.hasParam {
+				turn = t.param
+			}
+			dir -= turn
+		case '[':
+			stack = append(stack, struct{ x, y, d float64 }{x, y, dir})
+		case ']':
+			if len(stack) > 0 {
+				s := stack[len(stack)-1]
+				x, y, dir = s.x, s.y, s.d
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return pts
+}
@@ -1,13 +1,204 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"math/rand"
+	"os"
 	"time"
 )
 
+// applyInput executes one captured input byte against w (and tabIdx, for the
+// Tab-switches-info-panel key), exactly as the interactive loop always has.
+// It's shared by the live loop, --replay playback, and --headless playback
+// so recorded sessions apply their inputs identically to how they were
+// captured.
+func applyInput(w *World, tabIdx *int, tabCount int, b byte) (quit bool) {
+	switch b {
+	case 'q':
+		return true
+	case '\t':
+		*tabIdx = (*tabIdx + 1) % tabCount
+	case 's':
+		for _, o := range w.Organisms {
+			if o.Alive && o.Steps > MinSteps {
+				o.Steps--
+			}
+		}
+	case 'S':
+		for _, o := range w.Organisms {
+			if o.Alive && o.Steps < MaxSteps {
+				o.Steps++
+			}
+		}
+	case 'r', 'R':
+		for _, o := range w.Organisms {
+			cur := 0
+			for i, ot := range OrganismTypes {
+				if o.Name == ot.Name {
+					cur = i
+					break
+				}
+			}
+			nxt := (cur + 1) % len(OrganismTypes)
+			ot := OrganismTypes[nxt]
+			o.Name = ot.Name
+			o.Color = ot.Color
+			o.Albedo = ot.Albedo
+			o.TempOpt = ot.TempOpt
+			o.Axiom = ot.Axiom
+			o.Rule = ot.Rule
+			o.Rules = ot.Rules
+			o.Angle = ot.Angle
+			o.Steps = 1
+			o.Alive = true
+		}
+	case 'n':
+		*w = *NewWorld()
+	case 'b':
+		*w = *NewWorld()
+		for _, o := range w.Organisms {
+			ot := OrganismTypes[0]
+			o.Name = ot.Name
+			o.Color = ot.Color
+			o.Albedo = ot.Albedo
+			o.TempOpt = ot.TempOpt
+			o.Axiom = ot.Axiom
+			o.Rule = ot.Rule
+			o.Rules = ot.Rules
+			o.Angle = ot.Angle
+		}
+	case 'w':
+		*w = *NewWorld()
+		for _, o := range w.Organisms {
+			ot := OrganismTypes[1]
+			o.Name = ot.Name
+			o.Color = ot.Color
+			o.Albedo = ot.Albedo
+			o.TempOpt = ot.TempOpt
+			o.Axiom = ot.Axiom
+			o.Rule = ot.Rule
+			o.Rules = ot.Rules
+			o.Angle = ot.Angle
+		}
+	case 'm':
+		*w = *NewWorld()
+		for i, o := range w.Organisms {
+			ot := OrganismTypes[i%len(OrganismTypes)]
+			o.Name = ot.Name
+			o.Color = ot.Color
+			o.Albedo = ot.Albedo
+			o.TempOpt = ot.TempOpt
+			o.Axiom = ot.Axiom
+			o.Rule = ot.Rule
+			o.Rules = ot.Rules
+			o.Angle = ot.Angle
+		}
+	case '+':
+		w.SunPower += 0.05
+	case '-':
+		w.SunPower -= 0.05
+	}
+	return false
+}
+
+// meanTemp is the grid's average temperature, used by --headless's CSV.
+func meanTemp(w *World) float64 {
+	sum := 0.0
+	for y := 0; y < w.Height; y++ {
+		for x := 0; x < w.Width; x++ {
+			sum += w.GridTemp[y][x]
+		}
+	}
+	return sum / float64(w.Width*w.Height)
+}
+
+// writeCSVHeader and writeCSVRow emit the {tick, sun_power, alive_by_species,
+// mean_temp} frame log --headless mode produces for CI-diffable replays.
+func writeCSVHeader() {
+	fmt.Print("tick,sun_power,mean_temp")
+	for _, ot := range OrganismTypes {
+		fmt.Printf(",%s", ot.Name)
+	}
+	fmt.Println()
+}
+
+func writeCSVRow(w *World, tick int) {
+	aliveBySpecies := map[string]int{}
+	for _, o := range w.Organisms {
+		if o.Alive {
+			aliveBySpecies[o.Name]++
+		}
+	}
+	fmt.Printf("%d,%.4f,%.4f", tick, w.SunPower, meanTemp(w))
+	for _, ot := range OrganismTypes {
+		fmt.Printf(",%d", aliveBySpecies[ot.Name])
+	}
+	fmt.Println()
+}
+
+// runHeadless drives player to completion with no ANSI output, printing a
+// CSV frame log to stdout instead so runs can be diffed in CI.
+func runHeadless(w *World, player *Player) {
+	writeCSVHeader()
+	tabIdx := 0
+	for tick := 0; ; tick++ {
+		w.Update()
+		b, ok, more := player.Next()
+		if !more {
+			return
+		}
+		if ok && applyInput(w, &tabIdx, 1, b) {
+			return
+		}
+		writeCSVRow(w, tick)
+	}
+}
+
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	replayPath := flag.String("replay", "", "replay a recorded cast file instead of reading stdin")
+	recordPath := flag.String("record", "", "record every input byte plus the RNG seed to a cast file")
+	headless := flag.Bool("headless", false, "with --replay, run without ANSI output and emit a per-frame CSV")
+	speed := flag.Float64("speed", 1.0, "replay speed multiplier, ignored with --headless")
+	flag.Parse()
+
+	var player *Player
+	var recorder *Recorder
+	var seed int64
+
+	if *replayPath != "" {
+		p, err := LoadCast(*replayPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load cast file:", err)
+			os.Exit(1)
+		}
+		player = p
+		seed = p.Header.Seed
+	} else {
+		seed = time.Now().UnixNano()
+		if *recordPath != "" {
+			r, err := NewRecorder(*recordPath, seed)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to create cast file:", err)
+				os.Exit(1)
+			}
+			recorder = r
+			defer recorder.Close()
+		}
+	}
+	rand.Seed(seed)
+
 	w := NewWorld()
+
+	if *headless {
+		if player == nil {
+			fmt.Fprintln(os.Stderr, "--headless requires --replay")
+			os.Exit(1)
+		}
+		runHeadless(w, player)
+		return
+	}
+
 	tabs := InfoTabs()
 	tabIdx := 0
 
@@ -15,80 +206,29 @@ func main() {
 		PrintWorld(w, tabs, tabIdx)
 		w.Update()
 
-		// non-blocking read, but always advance one step per frame
-		if b, ok := NonBlockingRead(); ok {
-			switch b {
-			case 'q':
+		var b byte
+		var ok bool
+		if player != nil {
+			var more bool
+			b, ok, more = player.Next()
+			if !more {
 				return
-			case '\t':
-				tabIdx = (tabIdx + 1) % len(tabs)
-			case 's':
-				for _, o := range w.Organisms {
-					if o.Alive && o.Steps > MinSteps {
-						o.Steps--
-					}
-				}
-			case 'S':
-				for _, o := range w.Organisms {
-					if o.Alive && o.Steps < MaxSteps {
-						o.Steps++
-					}
-				}
-			case 'r', 'R':
-				for _, o := range w.Organisms {
-					cur := 0
-					for i, ot := range OrganismTypes {
-						if o.Name == ot.Name {
-							cur = i
-							break
-						}
-					}
-					nxt := (cur + 1) % len(OrganismTypes)
-					ot := OrganismTypes[nxt]
-					o.Name = ot.Name
-					o.Color = ot.Color
-					o.Albedo = ot.Albedo
-					o.TempOpt = ot.TempOpt
-					o.Axiom = ot.Axiom
-					o.Rule = ot.Rule
-					o.Angle = ot.Angle
-					o.Steps = 1
-					o.Alive = true
-				}
-			case 'n':
-				*w = *NewWorld()
-			case 'b':
-				*w = *NewWorld()
-				for _, o := range w.Organisms {
-					o.Name = OrganismTypes[0].Name
-					o.Color = OrganismTypes[0].Color
-					o.Albedo = OrganismTypes[0].Albedo
-					o.TempOpt = OrganismTypes[0].TempOpt
-				}
-			case 'w':
-				*w = *NewWorld()
-				for _, o := range w.Organisms {
-					o.Name = OrganismTypes[1].Name
-					o.Color = OrganismTypes[1].Color
-					o.Albedo = OrganismTypes[1].Albedo
-					o.TempOpt = OrganismTypes[1].TempOpt
-				}
-			case 'm':
-				*w = *NewWorld()
-				for i, o := range w.Organisms {
-					idx := i % len(OrganismTypes)
-					o.Name = OrganismTypes[idx].Name
-					o.Color = OrganismTypes[idx].Color
-					o.Albedo = OrganismTypes[idx].Albedo
-					o.TempOpt = OrganismTypes[idx].TempOpt
-				}
-			case '+':
-				w.SunPower += 0.05
-			case '-':
-				w.SunPower -= 0.05
 			}
+		} else {
+			b, ok = NonBlockingRead()
+			if recorder != nil {
+				recorder.RecordInput(b, ok)
+			}
+		}
+
+		if ok && applyInput(w, &tabIdx, len(tabs), b) {
+			return
 		}
-		time.Sleep(time.Duration(FrameDelay) * time.Millisecond)
+
+		delay := FrameDelay
+		if player != nil && *speed > 0 {
+			delay = int(float64(FrameDelay) / *speed)
+		}
+		time.Sleep(time.Duration(delay) * time.Millisecond)
 	}
 }
-
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math"
 	"os"
 	"strings"
   "time"
@@ -34,8 +33,7 @@ func PrintWorld(w *World, infoTabs []InfoWindow, tabIdx int) {
 	}
 	for _, o := range w.Organisms {
 		if o.Alive {
-			ls := ExpandLSystem(o.Axiom, o.Rule, o.Steps)
-			DrawLSystemToGrid(ls, o.Angle, grid, o.X, o.Y)
+			DrawLSystemToGrid(o.expand(), o.Angle, grid, o.X, o.Y)
 		}
 	}
 	fmt.Print("\033[H\033[2J")
@@ -62,48 +60,17 @@ func PrintWorld(w *World, infoTabs []InfoWindow, tabIdx int) {
 	PrintInfoTabs(infoTabs, tabIdx, w)
 }
 
+// DrawLSystemToGrid stamps an organism's turtle-graphics footprint (via
+// Render) into the display grid, offset from its (ox, oy) origin.
 func DrawLSystemToGrid(ls string, angle float64, grid [][]rune, ox, oy int) {
-	dir := -90.0
-	stack := []struct {
-		x, y int
-		d    float64
-	}{}
-	x, y := ox, oy
-	for _, c := range ls {
-		switch c {
-		case 'F':
-			nx := x + int(roundCos(dir))
-			ny := y + int(roundSin(dir))
-			if nx >= 0 && nx < len(grid[0]) && ny >= 0 && ny < len(grid) {
-				grid[ny][nx] = '█'
-			}
-			x, y = nx, ny
-		case '+':
-			dir += angle
-		case '-':
-			dir -= angle
-		case '[':
-			stack = append(stack, struct {
-				x, y int
-				d    float64
-			}{x, y, dir})
-		case ']':
-			if len(stack) > 0 {
-				s := stack[len(stack)-1]
-				x, y, dir = s.x, s.y, s.d
-				stack = stack[:len(stack)-1]
-			}
+	for _, p := range Render(ls, angle) {
+		nx, ny := ox+p.X, oy+p.Y
+		if nx >= 0 && nx < len(grid[0]) && ny >= 0 && ny < len(grid) {
+			grid[ny][nx] = '█'
 		}
 	}
 }
 
-func roundCos(deg float64) float64 {
-	return math.Round(math.Cos(deg * math.Pi / 180))
-}
-func roundSin(deg float64) float64 {
-	return math.Round(math.Sin(deg * math.Pi / 180))
-}
-
 func padOrClip(s string, w int) string {
 	rs := []rune(s)
 	if len(rs) > w {
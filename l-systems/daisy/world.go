@@ -25,6 +25,7 @@ type Organism struct {
 	Albedo   float64
 	TempOpt  float64
 	Rule     string
+	Rules    map[rune][]Production
 	Axiom    string
 	Angle    float64
 	Steps    int
@@ -32,6 +33,16 @@ type Organism struct {
 	Name     string
 }
 
+// expand produces this organism's current L-system string, using its
+// stochastic grammar (Rules) when it has one, falling back to the
+// deterministic Rule otherwise.
+func (o *Organism) expand() string {
+	if len(o.Rules) > 0 {
+		return ExpandStochastic(o.Axiom, o.Rules, o.Steps)
+	}
+	return ExpandLSystem(o.Axiom, o.Rule, o.Steps)
+}
+
 type World struct {
 	GridTemp   [][]float64
 	GridAlbedo [][]float64
@@ -60,6 +71,36 @@ var OrganismTypes = []Organism{
 		Rule:    "F[+F]F[-F]F",
 		Angle:   25.7,
 	},
+	{
+		Name:    "Mottled Daisy",
+		Color:   "yellow",
+		Albedo:  0.5,
+		TempOpt: 20.0,
+		Axiom:   "F",
+		Rules: map[rune][]Production{
+			'F': {
+				{Body: "F[+F]F[-F]F", Weight: 0.6},
+				{Body: "F[+F][-F]F", Weight: 0.3},
+				{Body: "FF", Weight: 0.1},
+			},
+		},
+		Angle: 22.5,
+	},
+	{
+		Name:    "Fern Daisy",
+		Color:   "green",
+		Albedo:  0.35,
+		TempOpt: 19.0,
+		Axiom:   "F(1)",
+		Rules: map[rune][]Production{
+			'F': {
+				{Body: "F(n*1.2)[+(25)F(n*0.7)][-(25)F(n*0.7)]F(n*0.9)", Weight: 0.7, Condition: "n<4"},
+				{Body: "F(n*1.1)[+(35)F(n*0.6)]F(n*0.8)", Weight: 0.3, Condition: "n<4"},
+				{Body: "F(n)", Weight: 1, Condition: "n>=4"},
+			},
+		},
+		Angle: 22.5,
+	},
 }
 
 func min(a, b int) int {
@@ -106,6 +147,7 @@ func NewWorld() *World {
 			TempOpt: ot.TempOpt,
 			Axiom:   ot.Axiom,
 			Rule:    ot.Rule,
+			Rules:   ot.Rules,
 			Angle:   ot.Angle,
 			Steps:   1,
 			Alive:   true,
@@ -123,8 +165,15 @@ func (w *World) Update() {
 		}
 	}
 	for _, o := range w.Organisms {
-		if o.Alive {
-			w.GridAlbedo[o.Y][o.X] = o.Albedo
+		if !o.Alive {
+			continue
+		}
+		w.GridAlbedo[o.Y][o.X] = o.Albedo
+		for _, p := range Render(o.expand(), o.Angle) {
+			nx, ny := o.X+p.X, o.Y+p.Y
+			if nx >= 0 && nx < w.Width && ny >= 0 && ny < w.Height {
+				w.GridAlbedo[ny][nx] = o.Albedo
+			}
 		}
 	}
 	for y := 0; y < w.Height; y++ {
@@ -193,6 +242,7 @@ func (w *World) Update() {
 						TempOpt: o.TempOpt,
 						Axiom:   o.Axiom,
 						Rule:    o.Rule,
+						Rules:   o.Rules,
 						Angle:   o.Angle,
 						Steps:   1,
 						Alive:   true,
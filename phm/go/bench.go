@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// benchStats accumulates -bench's client-side results so the redraw
+// goroutine and the worker goroutines can share them safely.
+type benchStats struct {
+	mu        sync.Mutex
+	latencies []float64 // seconds, successful requests only
+	errors    int
+}
+
+func (s *benchStats) record(seconds float64, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if failed {
+		s.errors++
+		return
+	}
+	s.latencies = append(s.latencies, seconds)
+}
+
+// snapshot returns a sorted copy of the latencies recorded so far, plus the
+// error and total request counts.
+func (s *benchStats) snapshot() (sorted []float64, errors, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sorted = append([]float64(nil), s.latencies...)
+	sort.Float64s(sorted)
+	return sorted, s.errors, len(s.latencies) + s.errors
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// renderHistogram draws a plow-style ASCII bar histogram of sorted (seconds)
+// over the same bucket boundaries /metrics uses.
+func renderHistogram(sorted []float64) string {
+	counts := make([]int, len(latencyBuckets)+1)
+	for _, s := range sorted {
+		placed := false
+		for i, b := range latencyBuckets {
+			if s <= b {
+				counts[i]++
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			counts[len(latencyBuckets)]++
+		}
+	}
+	maxCount := 1
+	for _, c := range counts {
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+	const barWidth = 40
+	var b bytes.Buffer
+	for i, bound := range latencyBuckets {
+		label := fmt.Sprintf("<=%-6g", bound)
+		bar := strings.Repeat("#", counts[i]*barWidth/maxCount)
+		fmt.Fprintf(&b, "  %-10s %-40s %d\n", label, bar, counts[i])
+	}
+	bar := strings.Repeat("#", counts[len(latencyBuckets)]*barWidth/maxCount)
+	fmt.Fprintf(&b, "  %-10s %-40s %d\n", "+Inf", bar, counts[len(latencyBuckets)])
+	return b.String()
+}
+
+func drawBenchProgress(stats *benchStats, started time.Time, target int) {
+	sorted, errors, total := stats.snapshot()
+	elapsed := time.Since(started).Seconds()
+	throughput := 0.0
+	if elapsed > 0 {
+		throughput = float64(total) / elapsed
+	}
+	fmt.Fprint(os.Stderr, "\033[H\033[2J")
+	fmt.Fprintf(os.Stderr, "ph-api bench: %d/%d requests, %.1f req/s, errors=%d\n", total, target, throughput, errors)
+	fmt.Fprintf(os.Stderr, "latency p50=%.1fms p90=%.1fms p99=%.1fms\n\n",
+		percentile(sorted, 0.50)*1000, percentile(sorted, 0.90)*1000, percentile(sorted, 0.99)*1000)
+	fmt.Fprint(os.Stderr, renderHistogram(sorted))
+}
+
+// runBench is the "-bench" client harness: it dials ph-api's own unix
+// socket and POSTs an image repeatedly, redrawing live latency percentiles,
+// throughput, and a histogram to stderr in the style of plow.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	concurrency := fs.Int("c", 10, "number of concurrent workers")
+	numRequests := fs.Int("n", 100, "total number of requests to send")
+	socket := fs.String("socket", socketPath, "unix socket ph-api is listening on")
+	imagePath := fs.String("image", "", "path to an image file to upload repeatedly")
+	fs.Parse(args)
+
+	if *imagePath == "" {
+		fmt.Fprintln(os.Stderr, "-bench requires -image <file>")
+		os.Exit(1)
+	}
+	imgBytes, err := ioutil.ReadFile(*imagePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read -image:", err)
+		os.Exit(1)
+	}
+	payload, err := json.Marshal(ImageUploadRequest{
+		Image: "data:image/png;base64," + base64.StdEncoding.EncodeToString(imgBytes),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to encode request:", err)
+		os.Exit(1)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", *socket)
+			},
+		},
+	}
+
+	stats := &benchStats{}
+	started := time.Now()
+
+	redrawDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				drawBenchProgress(stats, started, *numRequests)
+			case <-redrawDone:
+				return
+			}
+		}
+	}()
+
+	jobs := make(chan struct{}, *numRequests)
+	for i := 0; i < *numRequests; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				t0 := time.Now()
+				req, err := http.NewRequest(http.MethodPost, "http://unix/upload", bytes.NewReader(payload))
+				if err != nil {
+					stats.record(time.Since(t0).Seconds(), true)
+					continue
+				}
+				req.Header.Set("Authorization", "Bearer "+apiKey)
+				resp, err := client.Do(req)
+				elapsed := time.Since(t0).Seconds()
+				if err != nil {
+					stats.record(elapsed, true)
+					continue
+				}
+				io.Copy(ioutil.Discard, resp.Body)
+				resp.Body.Close()
+				stats.record(elapsed, resp.StatusCode != http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+	close(redrawDone)
+	drawBenchProgress(stats, started, *numRequests)
+	fmt.Fprintln(os.Stderr)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/base64"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
@@ -21,7 +23,9 @@ const (
 
 // ImageUploadRequest defines the expected JSON structure
 type ImageUploadRequest struct {
-	Image string `json:"image"`
+	Image     string `json:"image"`
+	Signature string `json:"signature,omitempty"` // detached Ed25519 signature over the decoded image, base64
+	KeyID     string `json:"key_id,omitempty"`
 }
 
 // Response struct
@@ -61,6 +65,8 @@ func getNextImageName() string {
 }
 
 func uploadHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
 	// Open debug log file
 	logFile, _ := os.OpenFile("/tmp/ph-api-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	defer logFile.Close()
@@ -72,6 +78,7 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		logger.Println("Invalid request method:", r.Method)
 		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+		recordUploadError("method_not_allowed", time.Since(start))
 		return
 	}
 
@@ -80,6 +87,7 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if authHeader != "Bearer "+apiKey {
 		logger.Println("Unauthorized access attempt")
 		http.Error(w, `{"error": "Unauthorized"}`, http.StatusUnauthorized)
+		recordUploadError("unauthorized", time.Since(start))
 		return
 	}
 
@@ -89,6 +97,7 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.Println("Invalid JSON payload:", err)
 		http.Error(w, `{"error": "Invalid JSON"}`, http.StatusBadRequest)
+		recordUploadError("invalid_json", time.Since(start))
 		return
 	}
 
@@ -96,6 +105,7 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if len(req.Image) < 20 {
 		logger.Println("Base64 string too short, rejecting request")
 		http.Error(w, `{"error": "Invalid Base64 string"}`, http.StatusBadRequest)
+		recordUploadError("invalid_base64", time.Since(start))
 		return
 	}
 
@@ -117,6 +127,7 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		logger.Println("MIME prefix missing, rejecting request")
 		http.Error(w, `{"error": "Invalid image format"}`, http.StatusBadRequest)
+		recordUploadError("invalid_mime", time.Since(start))
 		return
 	}
 
@@ -130,12 +141,34 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.Println("Base64 decode error:", err)
 		http.Error(w, `{"error": "Failed to decode image"}`, http.StatusInternalServerError)
+		recordUploadError("base64_decode_failed", time.Since(start))
 		return
 	}
 
 	// Save decoded image for debugging
 	ioutil.WriteFile("/tmp/ph-api-decoded.png", imageData, 0644)
 
+	// Signature verification: optional unless the server was started with
+	// --require-signatures, in which case an unsigned request is rejected.
+	signed := false
+	if req.Signature == "" {
+		if *requireSignatures {
+			logger.Println("Missing signature, rejecting (require-signatures mode)")
+			http.Error(w, `{"error": "Signature required"}`, http.StatusUnauthorized)
+			recordUploadError("signature_required", time.Since(start))
+			return
+		}
+	} else {
+		ok, err := verifySignature(imageData, req.Signature, req.KeyID)
+		if err != nil || !ok {
+			logger.Println("Signature verification failed:", err)
+			http.Error(w, `{"error": "Signature verification failed"}`, http.StatusUnauthorized)
+			recordUploadError("signature_invalid", time.Since(start))
+			return
+		}
+		signed = true
+	}
+
 	// Ensure upload directory exists
 	ensureUploadDir()
 
@@ -148,11 +181,19 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.Println("Failed to save image:", err)
 		http.Error(w, `{"error": "Failed to save image"}`, http.StatusInternalServerError)
+		recordUploadError("write_failed", time.Since(start))
 		return
 	}
 
 	logger.Println("Image saved successfully:", filename)
 
+	if signed {
+		sidecar, _ := json.Marshal(sigSidecar{KeyID: req.KeyID, Signature: req.Signature})
+		ioutil.WriteFile(filePath+".sig", sidecar, 0644)
+	}
+
+	recordUploadSuccess(len(imageData), time.Since(start))
+
 	// Return success response
 	response := UploadResponse{
 		Message: "Upload successful",
@@ -164,8 +205,19 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	// "-bench" is a separate client-harness subcommand, not a server flag,
+	// so it's dispatched before flag.Parse() touches the server's own flags.
+	if len(os.Args) > 1 && os.Args[1] == "-bench" {
+		runBench(os.Args[2:])
+		return
+	}
+
+	flag.Parse()
+	trustedKeys = loadTrustedKeys()
+
 	// Ensure upload directory exists at startup
 	ensureUploadDir()
+	ensureOCIDirs()
 
 	// Remove previous socket if exists
 	if _, err := os.Stat(socketPath); err == nil {
@@ -186,6 +238,9 @@ func main() {
 
 	fmt.Println("ph-api listening on", socketPath)
 	http.HandleFunc("/upload", uploadHandler)
+	http.HandleFunc("/v2/", v2Handler)
+	http.HandleFunc("/verify/", verifyHandler)
+	http.HandleFunc("/metrics", metricsHandler)
 
 	// Start HTTP server over UNIX socket
 	err = http.Serve(listener, nil)
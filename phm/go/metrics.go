@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the Prometheus histogram boundaries (seconds) for
+// upload_latency_seconds, shared by the server's own /metrics and by -bench
+// when it renders its own live histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var (
+	uploadsTotal     uint64
+	uploadBytesTotal uint64
+
+	uploadErrorsMu    sync.Mutex
+	uploadErrorsTotal = map[string]uint64{}
+
+	latencyMu      sync.Mutex
+	latencyCounts  = make([]uint64, len(latencyBuckets)+1) // last slot is +Inf
+	latencySum     float64
+	latencyCount   uint64
+)
+
+func recordUploadSuccess(bytes int, elapsed time.Duration) {
+	atomic.AddUint64(&uploadsTotal, 1)
+	atomic.AddUint64(&uploadBytesTotal, uint64(bytes))
+	recordLatency(elapsed)
+}
+
+func recordUploadError(reason string, elapsed time.Duration) {
+	uploadErrorsMu.Lock()
+	uploadErrorsTotal[reason]++
+	uploadErrorsMu.Unlock()
+	recordLatency(elapsed)
+}
+
+func recordLatency(elapsed time.Duration) {
+	seconds := elapsed.Seconds()
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	latencyCount++
+	latencySum += seconds
+	for i, b := range latencyBuckets {
+		if seconds <= b {
+			latencyCounts[i]++
+		}
+	}
+	latencyCounts[len(latencyBuckets)]++
+}
+
+// metricsHandler serves Prometheus text-format counters and a latency
+// histogram for the upload endpoint, so `-bench` runs and real deployments
+// can be scraped the same way.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP uploads_total Total successful image uploads.")
+	fmt.Fprintln(w, "# TYPE uploads_total counter")
+	fmt.Fprintf(w, "uploads_total %d\n", atomic.LoadUint64(&uploadsTotal))
+
+	fmt.Fprintln(w, "# HELP upload_bytes_total Total bytes of decoded image data written.")
+	fmt.Fprintln(w, "# TYPE upload_bytes_total counter")
+	fmt.Fprintf(w, "upload_bytes_total %d\n", atomic.LoadUint64(&uploadBytesTotal))
+
+	fmt.Fprintln(w, "# HELP upload_errors_total Upload requests rejected, by reason.")
+	fmt.Fprintln(w, "# TYPE upload_errors_total counter")
+	uploadErrorsMu.Lock()
+	reasons := make([]string, 0, len(uploadErrorsTotal))
+	for reason := range uploadErrorsTotal {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "upload_errors_total{reason=\"%s\"} %d\n", reason, uploadErrorsTotal[reason])
+	}
+	uploadErrorsMu.Unlock()
+
+	fmt.Fprintln(w, "# HELP upload_latency_seconds Upload request latency in seconds.")
+	fmt.Fprintln(w, "# TYPE upload_latency_seconds histogram")
+	latencyMu.Lock()
+	for i, b := range latencyBuckets {
+		fmt.Fprintf(w, "upload_latency_seconds_bucket{le=\"%g\"} %d\n", b, latencyCounts[i])
+	}
+	fmt.Fprintf(w, "upload_latency_seconds_bucket{le=\"+Inf\"} %d\n", latencyCounts[len(latencyBuckets)])
+	fmt.Fprintf(w, "upload_latency_seconds_sum %f\n", latencySum)
+	fmt.Fprintf(w, "upload_latency_seconds_count %d\n", latencyCount)
+	latencyMu.Unlock()
+}
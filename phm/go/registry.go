@@ -0,0 +1,268 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// OCI-compatible blob/manifest storage, layered under uploadDir so pushes
+// from clients like crane or skopeo land next to the existing img1.png-style
+// uploads instead of a separate tree.
+const (
+	ociBlobsDir     = uploadDir + "blobs/sha256/"
+	ociManifestsDir = uploadDir + "manifests/"
+	ociUploadsDir   = uploadDir + "uploads/"
+)
+
+func ensureOCIDirs() {
+	for _, d := range []string{ociBlobsDir, ociManifestsDir, ociUploadsDir} {
+		if _, err := os.Stat(d); os.IsNotExist(err) {
+			if err := os.MkdirAll(d, 0755); err != nil {
+				fmt.Println("Failed to create OCI directory:", d, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// uploadSessions tracks in-progress chunked blob uploads by UUID, mapping
+// each to the temp file under ociUploadsDir its chunks are appended to.
+var (
+	uploadSessionsMu sync.Mutex
+	uploadSessions   = map[string]string{}
+)
+
+func newUploadUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// checkAPIKey reports whether r carries the same bearer token uploadHandler
+// requires.
+func checkAPIKey(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer "+apiKey
+}
+
+// v2Handler dispatches the minimal registry v2 API: version check, blob
+// upload (POST/PATCH/PUT), blob fetch (GET/HEAD), and manifest push/fetch
+// (PUT/GET). It reuses apiKey bearer auth for every sub-route.
+func v2Handler(w http.ResponseWriter, r *http.Request) {
+	if !checkAPIKey(r) {
+		http.Error(w, `{"error": "Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	if path == "" {
+		w.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch {
+	case strings.Contains(path, "/blobs/uploads/"):
+		idx := strings.Index(path, "/blobs/uploads/")
+		name := path[:idx]
+		rest := path[idx+len("/blobs/uploads/"):]
+		if rest == "" {
+			blobUploadStartHandler(w, r, name)
+		} else {
+			blobUploadChunkHandler(w, r, name, rest)
+		}
+	case strings.Contains(path, "/blobs/"):
+		idx := strings.Index(path, "/blobs/")
+		name := path[:idx]
+		digest := path[idx+len("/blobs/"):]
+		blobHandler(w, r, name, digest)
+	case strings.Contains(path, "/manifests/"):
+		idx := strings.Index(path, "/manifests/")
+		name := path[:idx]
+		reference := path[idx+len("/manifests/"):]
+		manifestHandler(w, r, name, reference)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// blobUploadStartHandler begins a chunked upload session (POST .../blobs/uploads/).
+func blobUploadStartHandler(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+	uuid := newUploadUUID()
+	sessionPath := ociUploadsDir + uuid
+	if err := ioutil.WriteFile(sessionPath, nil, 0644); err != nil {
+		http.Error(w, `{"error": "Failed to start upload"}`, http.StatusInternalServerError)
+		return
+	}
+	uploadSessionsMu.Lock()
+	uploadSessions[uuid] = sessionPath
+	uploadSessionsMu.Unlock()
+
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", name, uuid))
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// blobUploadChunkHandler appends a PATCH chunk to an upload session, or
+// finalizes it on PUT by verifying the digest and moving the blob into
+// blobs/sha256/<digest>.
+func blobUploadChunkHandler(w http.ResponseWriter, r *http.Request, name, uuid string) {
+	uploadSessionsMu.Lock()
+	sessionPath, ok := uploadSessions[uuid]
+	uploadSessionsMu.Unlock()
+	if !ok {
+		http.Error(w, `{"error": "Unknown upload"}`, http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, `{"error": "Failed to read chunk"}`, http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		f, err := os.OpenFile(sessionPath, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			http.Error(w, `{"error": "Failed to append chunk"}`, http.StatusInternalServerError)
+			return
+		}
+		_, werr := f.Write(body)
+		f.Close()
+		if werr != nil {
+			http.Error(w, `{"error": "Failed to append chunk"}`, http.StatusInternalServerError)
+			return
+		}
+		info, _ := os.Stat(sessionPath)
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", name, uuid))
+		w.Header().Set("Range", fmt.Sprintf("0-%d", info.Size()-1))
+		w.WriteHeader(http.StatusAccepted)
+
+	case http.MethodPut:
+		if len(body) > 0 {
+			f, err := os.OpenFile(sessionPath, os.O_APPEND|os.O_WRONLY, 0644)
+			if err == nil {
+				f.Write(body)
+				f.Close()
+			}
+		}
+		digest := r.URL.Query().Get("digest")
+		data, err := ioutil.ReadFile(sessionPath)
+		if err != nil {
+			http.Error(w, `{"error": "Failed to read upload"}`, http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(data)
+		got := "sha256:" + hex.EncodeToString(sum[:])
+		if digest != got {
+			http.Error(w, `{"error": "Digest mismatch"}`, http.StatusBadRequest)
+			return
+		}
+
+		ensureOCIDirs()
+		blobPath := ociBlobsDir + strings.TrimPrefix(digest, "sha256:")
+		if err := ioutil.WriteFile(blobPath, data, 0644); err != nil {
+			http.Error(w, `{"error": "Failed to store blob"}`, http.StatusInternalServerError)
+			return
+		}
+		os.Remove(sessionPath)
+		uploadSessionsMu.Lock()
+		delete(uploadSessions, uuid)
+		uploadSessionsMu.Unlock()
+
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/%s", name, digest))
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+// blobHandler serves (GET) or checks existence of (HEAD) a content-addressed
+// blob by its sha256 digest.
+func blobHandler(w http.ResponseWriter, r *http.Request, name, digest string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+	blobPath := ociBlobsDir + strings.TrimPrefix(digest, "sha256:")
+	info, err := os.Stat(blobPath)
+	if err != nil {
+		http.Error(w, `{"error": "Blob not found"}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", info.Size()))
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	data, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		http.Error(w, `{"error": "Blob not found"}`, http.StatusNotFound)
+		return
+	}
+	w.Write(data)
+}
+
+// manifestHandler pushes (PUT) or fetches (GET) a manifest stored under
+// manifests/<name>/<reference>, where reference is a tag (e.g. "latest") or
+// a digest.
+func manifestHandler(w http.ResponseWriter, r *http.Request, name, reference string) {
+	dir := ociManifestsDir + name
+	path := filepath.Join(dir, reference)
+
+	switch r.Method {
+	case http.MethodPut:
+		ct := r.Header.Get("Content-Type")
+		if !strings.Contains(ct, "vnd.oci.image.manifest") && !strings.Contains(ct, "vnd.docker.distribution.manifest") {
+			http.Error(w, `{"error": "Unsupported manifest content type"}`, http.StatusBadRequest)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, `{"error": "Failed to read manifest"}`, http.StatusBadRequest)
+			return
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			http.Error(w, `{"error": "Failed to create manifest dir"}`, http.StatusInternalServerError)
+			return
+		}
+		if err := ioutil.WriteFile(path, body, 0644); err != nil {
+			http.Error(w, `{"error": "Failed to store manifest"}`, http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(body)
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/manifests/%s", name, reference))
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodGet:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			http.Error(w, `{"error": "Manifest not found"}`, http.StatusNotFound)
+			return
+		}
+		sum := sha256.Sum256(data)
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		w.Header().Set("Docker-Content-Digest", "sha256:"+hex.EncodeToString(sum[:]))
+		w.Write(data)
+
+	default:
+		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// trustedKeysEnvVar names the environment variable pointing at a JSON file
+// of key_id -> base64-encoded Ed25519 public key, loaded once at startup.
+const trustedKeysEnvVar = "PH_API_TRUSTED_KEYS"
+
+// requireSignatures, when set via --require-signatures, rejects any upload
+// that doesn't carry a verifiable signature.
+var requireSignatures = flag.Bool("require-signatures", false, "reject image uploads that lack a valid signature")
+
+var trustedKeys map[string]ed25519.PublicKey
+
+// sigSidecar is the JSON stored alongside an uploaded image as imgN.png.sig.
+type sigSidecar struct {
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"`
+}
+
+// VerifyResponse is returned by GET /verify/<filename>.
+type VerifyResponse struct {
+	KeyID    string `json:"key_id"`
+	Verified bool   `json:"verified"`
+}
+
+// loadTrustedKeys reads the JSON key set named by trustedKeysEnvVar, if set.
+// A missing env var or unreadable file yields an empty (trust nothing) set
+// rather than a startup failure, since signatures are optional by default.
+func loadTrustedKeys() map[string]ed25519.PublicKey {
+	keys := map[string]ed25519.PublicKey{}
+	path := os.Getenv(trustedKeysEnvVar)
+	if path == "" {
+		return keys
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Println("Failed to read trusted keys file:", err)
+		return keys
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		fmt.Println("Failed to parse trusted keys file:", err)
+		return keys
+	}
+	for keyID, b64 := range raw {
+		pub, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			fmt.Println("Invalid public key for key_id:", keyID)
+			continue
+		}
+		keys[keyID] = ed25519.PublicKey(pub)
+	}
+	return keys
+}
+
+// verifySignature checks a base64 detached Ed25519 signature over image
+// against the trusted public key registered under keyID.
+func verifySignature(image []byte, signatureB64, keyID string) (bool, error) {
+	pub, ok := trustedKeys[keyID]
+	if !ok {
+		return false, fmt.Errorf("unknown key_id: %s", keyID)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	return ed25519.Verify(pub, image, sig), nil
+}
+
+// verifyHandler serves GET /verify/<filename>, re-verifying the stored
+// imgN.png against its imgN.png.sig sidecar (if any) and reporting the
+// result without requiring the caller to resubmit the image or signature.
+func verifyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error": "Method Not Allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	filename := strings.TrimPrefix(r.URL.Path, "/verify/")
+	imgPath := filepath.Join(uploadDir, filename)
+	image, err := ioutil.ReadFile(imgPath)
+	if err != nil {
+		http.Error(w, `{"error": "Image not found"}`, http.StatusNotFound)
+		return
+	}
+
+	resp := VerifyResponse{}
+	sidecar, err := ioutil.ReadFile(imgPath + ".sig")
+	if err == nil {
+		var sc sigSidecar
+		if json.Unmarshal(sidecar, &sc) == nil {
+			resp.KeyID = sc.KeyID
+			resp.Verified, _ = verifySignature(image, sc.Signature, sc.KeyID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
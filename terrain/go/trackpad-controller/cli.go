@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runCaptureCLI implements the micro-style `capture record <file>` /
+// `capture play <file>` / `capture raw [flags]` / `capture joystick [flags]`
+// subcommands. It reports whether args were handled as a subcommand at
+// all, so main() can fall through to the live demo otherwise.
+func runCaptureCLI(args []string) (handled bool, err error) {
+	if len(args) < 1 {
+		return false, nil
+	}
+	switch args[0] {
+	case "record":
+		if len(args) < 2 {
+			return false, nil
+		}
+		return true, runRecord(args[1])
+	case "play":
+		if len(args) < 2 {
+			return false, nil
+		}
+		return true, runPlay(args[1])
+	case "raw":
+		return true, runRaw(args[1:])
+	case "joystick":
+		return true, runJoystick(args[1:])
+	default:
+		return false, nil
+	}
+}
+
+func runRecord(path string) error {
+	capture, err := NewInputCapture(Options{BlockEvents: false})
+	if err != nil {
+		return err
+	}
+	if err := capture.Start(); err != nil {
+		return err
+	}
+	defer capture.Stop()
+
+	rec, err := NewFileRecorder(path, capture)
+	if err != nil {
+		return err
+	}
+	defer rec.Close()
+
+	fmt.Printf("Recording to %s — press Ctrl+C to stop\n", path)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+	return nil
+}
+
+func runPlay(path string) error {
+	replayer := NewFileReplayer(path, 1.0, false)
+	if err := replayer.Start(); err != nil {
+		return err
+	}
+	defer replayer.Stop()
+
+	fmt.Printf("Replaying %s\n", path)
+	for ev := range replayer.Events() {
+		fmt.Printf("%s at (%.1f, %.1f) buttons=%d\n", ev.Type, ev.X, ev.Y, ev.Buttons)
+	}
+	return nil
+}
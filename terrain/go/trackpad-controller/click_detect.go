@@ -0,0 +1,124 @@
+package main
+
+// ClickDownEvent and ClickUpEvent are edge-triggered: exactly one fires
+// per touch the frame it engages/disengages, derived from State the
+// same way a real trackpad's physical click is — MakeTouch/Touching mean
+// the contact is firmly down, BreakTouch means it's lifting.
+type ClickDownEvent struct {
+	Identifier int
+}
+
+type ClickUpEvent struct {
+	Identifier int
+}
+
+// ForceTouchEvent fires once per touch the frame its normalized Pressure
+// first crosses ForceThreshold, mirroring macOS's force-click without
+// depending on AppKit.
+type ForceTouchEvent struct {
+	Identifier int
+	Pressure   float64
+}
+
+// ForceTouchConfig tunes click/force-touch detection. DensityMax
+// normalizes the raw Density field (whose native units and range are
+// undocumented) into Pressure's 0..1 scale.
+type ForceTouchConfig struct {
+	DensityMax     float64
+	ForceThreshold float64 // normalized Pressure at or above this is a force touch
+}
+
+// DefaultForceTouchConfig returns thresholds tuned for the Density
+// magnitudes this package's recorded sessions have observed.
+func DefaultForceTouchConfig() ForceTouchConfig {
+	return ForceTouchConfig{
+		DensityMax:     50.0,
+		ForceThreshold: 0.8,
+	}
+}
+
+// isClicked reports whether state corresponds to a firmly-down contact,
+// as opposed to one still hovering or only lingering after breaking
+// contact.
+func isClicked(state TouchState) bool {
+	return state == TouchMakeTouch || state == TouchTouching
+}
+
+// ClickDetector tracks each touch's last known Click/force-touch state
+// across frames so it can emit ClickDownEvent/ClickUpEvent/
+// ForceTouchEvent exactly once per transition.
+type ClickDetector struct {
+	cfg     ForceTouchConfig
+	clicked map[int]bool
+	forced  map[int]bool
+}
+
+// NewClickDetector builds a detector with cfg; pass a zero-value
+// ForceTouchConfig to use DefaultForceTouchConfig.
+func NewClickDetector(cfg ForceTouchConfig) *ClickDetector {
+	if cfg == (ForceTouchConfig{}) {
+		cfg = DefaultForceTouchConfig()
+	}
+	return &ClickDetector{
+		cfg:     cfg,
+		clicked: make(map[int]bool),
+		forced:  make(map[int]bool),
+	}
+}
+
+// Feed derives Click and Pressure for every touch in frame, returning
+// the enriched frame alongside any ClickDownEvent/ClickUpEvent/
+// ForceTouchEvent the transition from the previous frame implies.
+func (d *ClickDetector) Feed(frame TouchFrame) (TouchFrame, []interface{}) {
+	var events []interface{}
+	seen := make(map[int]bool, len(frame.Touches))
+
+	out := frame
+	out.Touches = make([]Touch, len(frame.Touches))
+	for i, t := range frame.Touches {
+		seen[t.Identifier] = true
+
+		t.Click = isClicked(t.State)
+		if d.cfg.DensityMax > 0 {
+			t.Pressure = clamp01(t.Density / d.cfg.DensityMax)
+		}
+
+		if t.Click && !d.clicked[t.Identifier] {
+			events = append(events, ClickDownEvent{Identifier: t.Identifier})
+		} else if !t.Click && d.clicked[t.Identifier] {
+			events = append(events, ClickUpEvent{Identifier: t.Identifier})
+		}
+		d.clicked[t.Identifier] = t.Click
+
+		if t.Pressure >= d.cfg.ForceThreshold && !d.forced[t.Identifier] {
+			events = append(events, ForceTouchEvent{Identifier: t.Identifier, Pressure: t.Pressure})
+			d.forced[t.Identifier] = true
+		} else if t.Pressure < d.cfg.ForceThreshold {
+			d.forced[t.Identifier] = false
+		}
+
+		out.Touches[i] = t
+	}
+
+	for id := range d.clicked {
+		if !seen[id] {
+			if d.clicked[id] {
+				events = append(events, ClickUpEvent{Identifier: id})
+			}
+			delete(d.clicked, id)
+			delete(d.forced, id)
+		}
+	}
+
+	return out, events
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
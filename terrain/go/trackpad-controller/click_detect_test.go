@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// recordFrames writes frames through a RecordingSink and replays them
+// back via a Player, so the detector under test sees exactly the frames
+// a real capture session would have produced.
+func recordFrames(t *testing.T, frames []TouchFrame) []TouchFrame {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "frames-*.jsonl")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	path := f.Name()
+	f.Close()
+
+	src := make(chan TouchFrame, len(frames))
+	sink, err := NewRecordingSink(path, src)
+	if err != nil {
+		t.Fatalf("NewRecordingSink: %v", err)
+	}
+	for _, fr := range frames {
+		src <- fr
+	}
+	sink.Close()
+
+	player := NewPlayer(path, 1000.0)
+	if err := player.Start(); err != nil {
+		t.Fatalf("player.Start: %v", err)
+	}
+	defer player.Stop()
+
+	var replayed []TouchFrame
+	for fr := range player.Channel() {
+		replayed = append(replayed, fr)
+	}
+	return replayed
+}
+
+func TestClickDetectorClickDownUp(t *testing.T) {
+	frames := recordFrames(t, []TouchFrame{
+		{Frame: 1, Touches: []Touch{{Identifier: 0, State: TouchHoverInRange}}},
+		{Frame: 2, Touches: []Touch{{Identifier: 0, State: TouchTouching}}},
+		{Frame: 3, Touches: []Touch{{Identifier: 0, State: TouchBreakTouch}}},
+	})
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 replayed frames, got %d", len(frames))
+	}
+
+	d := NewClickDetector(DefaultForceTouchConfig())
+	var allEvents []interface{}
+	for _, fr := range frames {
+		_, events := d.Feed(fr)
+		allEvents = append(allEvents, events...)
+	}
+
+	var downs, ups int
+	for _, ev := range allEvents {
+		switch ev.(type) {
+		case ClickDownEvent:
+			downs++
+		case ClickUpEvent:
+			ups++
+		}
+	}
+	if downs != 1 || ups != 1 {
+		t.Fatalf("expected exactly one ClickDownEvent and one ClickUpEvent, got %+v", allEvents)
+	}
+}
+
+func TestClickDetectorForceTouch(t *testing.T) {
+	cfg := ForceTouchConfig{DensityMax: 10.0, ForceThreshold: 0.8}
+	d := NewClickDetector(cfg)
+
+	light, _ := d.Feed(TouchFrame{Touches: []Touch{{Identifier: 0, State: TouchTouching, Density: 2}}})
+	if light.Touches[0].Pressure != 0.2 {
+		t.Fatalf("expected normalized pressure 0.2, got %v", light.Touches[0].Pressure)
+	}
+
+	_, events := d.Feed(TouchFrame{Touches: []Touch{{Identifier: 0, State: TouchTouching, Density: 9}}})
+	found := false
+	for _, ev := range events {
+		if ft, ok := ev.(ForceTouchEvent); ok {
+			found = true
+			if ft.Identifier != 0 {
+				t.Fatalf("unexpected force touch identifier: %+v", ft)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected a ForceTouchEvent once pressure crossed the threshold, got %+v", events)
+	}
+
+	// Feeding the same high density again should not re-fire.
+	_, events = d.Feed(TouchFrame{Touches: []Touch{{Identifier: 0, State: TouchTouching, Density: 9.5}}})
+	for _, ev := range events {
+		if _, ok := ev.(ForceTouchEvent); ok {
+			t.Fatalf("expected no repeat ForceTouchEvent while pressure stays above threshold, got %+v", events)
+		}
+	}
+}
@@ -1,14 +1,14 @@
+//go:build darwin
+
 package main
 
 /*
 #cgo CFLAGS: -x objective-c
-#cgo LDFLAGS: -framework ApplicationServices -framework CoreFoundation -F/System/Library/PrivateFrameworks -framework MultitouchSupport
+#cgo LDFLAGS: -framework ApplicationServices -framework CoreFoundation
 
 #include <ApplicationServices/ApplicationServices.h>
 #include <CoreFoundation/CoreFoundation.h>
 
-extern void goEventCallback(int eventType, double x, double y);
-
 static CGEventRef eventTapCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon) {
     // If blocking is enabled, block ALL events (including single-finger trackpad movement)
     if (refcon && *(int*)refcon) {
@@ -20,7 +20,7 @@ static CGEventRef eventTapCallback(CGEventTapProxy proxy, CGEventType type, CGEv
 static CFMachPortRef createEventTap(int *blockEvents) {
     // Capture ALL events to ensure we block cursor movement
     CGEventMask eventMask = kCGEventMaskForAllEvents;
-    
+
     CFMachPortRef eventTap = CGEventTapCreate(
         kCGHIDEventTap,
         kCGHeadInsertEventTap,
@@ -32,78 +32,20 @@ static CFMachPortRef createEventTap(int *blockEvents) {
 
     return eventTap;
 }
-
-typedef struct {
-    float x, y;
-} MTPoint;
-
-typedef struct {
-    MTPoint position;
-    MTPoint velocity;
-} MTVector;
-
-typedef struct {
-    int32_t frame;
-    double timestamp;
-    int32_t identifier;
-    int32_t state;
-    int32_t unknown1;
-    int32_t unknown2;
-    MTVector normalized;
-    float size;
-    int32_t unknown3;
-    float angle;
-    float majorAxis;
-    float minorAxis;
-    MTVector absolute;
-    int32_t unknown4;
-    int32_t unknown5;
-    float density;
-} MTTouch;
-
-typedef void* MTDeviceRef;
-typedef int (*MTContactCallbackFunction)(int, MTTouch*, int, double, int);
-
-extern CFArrayRef MTDeviceCreateList();
-extern MTDeviceRef MTDeviceCreateDefault();
-extern void MTRegisterContactFrameCallback(MTDeviceRef, MTContactCallbackFunction);
-extern void MTDeviceStart(MTDeviceRef, int);
-extern void MTDeviceStop(MTDeviceRef);
-
-extern int goMultitouchCallback(int device, MTTouch* touches, int numTouches, double timestamp, int frame);
-
-static int multitouchBridge(int device, MTTouch* touches, int numTouches, double timestamp, int frame) {
-    return goMultitouchCallback(device, touches, numTouches, timestamp, frame);
-}
-
-static MTDeviceRef setupMultitouch() {
-    MTDeviceRef device = MTDeviceCreateDefault();
-    if (device) {
-        MTRegisterContactFrameCallback(device, multitouchBridge);
-        MTDeviceStart(device, 0);
-    }
-    return device;
-}
 */
 import "C"
 import (
+	"flag"
 	"fmt"
-	"log"
 	"math"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 	"unsafe"
-)
 
-type Touch struct {
-	ID        int
-	X, Y      float64
-	VelX, VelY float64
-	Size      float64
-	Angle     float64
-}
+	"github.com/mricos/demos/terrain/go/trackpad-controller/gestures"
+)
 
 type ControllerState struct {
 	ActiveTouches map[int]Touch
@@ -112,22 +54,117 @@ type ControllerState struct {
 	Radius        float64
 }
 
+// Config tunes TrackpadController's palm/thumb rejection and pointer
+// acceleration, named after mtrack's palm_size/palm_width options and
+// this repo's own vscroll_fraction-style tunables for multitouch math.
+type Config struct {
+	PalmSizeMax         float64 // Size at or above this is rejected as a palm
+	PalmMajorAxisMax    float64 // MajorAxis at or above this is rejected as a palm
+	PalmEccentricityMax float64 // MajorAxis/MinorAxis at or above this is rejected as a palm
+	ThumbYMin           float64 // touches with Y below this are thumbs: kept for clicks, excluded from joystick math
+
+	Deadzone    float64 // |axis value| below this maps to 0
+	LinearSpeed float64 // slope of the linear low-speed region
+	QuadSpeed   float64 // coefficient of the quadratic high-speed region
+	Saturation  float64 // output magnitude cap
+}
+
+// DefaultConfig returns thresholds tuned for MultitouchSupport's
+// normalized (0..1-ish) size/axis units and a joystick range of [-1, 1].
+func DefaultConfig() Config {
+	return Config{
+		PalmSizeMax:         20.0,
+		PalmMajorAxisMax:    15.0,
+		PalmEccentricityMax: 3.0,
+		ThumbYMin:           0.12,
+		Deadzone:            0.03,
+		LinearSpeed:         1.0,
+		QuadSpeed:           2.5,
+		Saturation:          1.0,
+	}
+}
+
+// classify reports whether t should be rejected outright as a palm
+// (discarded from ActiveTouches entirely) or treated as a thumb (kept
+// for click detection but excluded from joystick math), mirroring
+// mtrack's mtstate offset/edge handling for the bottom of the pad.
+func (cfg Config) classify(t Touch) (isPalm, isThumb bool) {
+	ecc := 0.0
+	if t.MinorAxis > 0 {
+		ecc = t.MajorAxis / t.MinorAxis
+	}
+	if t.Size >= cfg.PalmSizeMax || t.MajorAxis >= cfg.PalmMajorAxisMax || ecc >= cfg.PalmEccentricityMax {
+		return true, false
+	}
+	if t.Y < cfg.ThumbYMin {
+		return false, true
+	}
+	return false, false
+}
+
+// accelerate maps a joystick axis value in [-1, 1] through a deadzone, a
+// linear low-speed region, and a quadratic high-speed region capped at
+// Saturation, so small movements near center stay precise while larger
+// ones reach full speed quickly instead of scaling 1:1.
+func (cfg Config) accelerate(v float64) float64 {
+	sign := 1.0
+	if v < 0 {
+		sign, v = -1.0, -v
+	}
+	const kneeSpeed = 0.6
+	switch {
+	case v < cfg.Deadzone:
+		v = 0
+	case v < kneeSpeed:
+		v = cfg.LinearSpeed * (v - cfg.Deadzone)
+	default:
+		v = cfg.LinearSpeed*(kneeSpeed-cfg.Deadzone) + cfg.QuadSpeed*(v-kneeSpeed)*(v-kneeSpeed)
+	}
+	if v > cfg.Saturation {
+		v = cfg.Saturation
+	}
+	return sign * v
+}
+
 var (
-	controller  *TrackpadController
-	eventTap    C.CFMachPortRef
-	mtDevice    C.MTDeviceRef
-	runLoopSrc  C.CFRunLoopSourceRef
-	blockFlag   *C.int
+	controller   *TrackpadController
+	eventTap     C.CFMachPortRef
+	runLoopSrc   C.CFRunLoopSourceRef
+	blockFlag    *C.int
+	multitouch   *MultitouchCapture
+	virtualInput *VirtualInputController
+	recognizer   = gestures.NewRecognizer(gestures.DefaultConfig())
 )
 
+// gestureKindFor maps a gestures.Recognizer event to the GestureKind and
+// finger count virtualInput.HandleGesture binds against. Rotate and tap
+// events have no remapping action yet, so they fall through unmatched.
+func gestureKindFor(ev interface{}) (kind GestureKind, fingers int, ok bool) {
+	switch e := ev.(type) {
+	case gestures.PinchEvent:
+		return GesturePinch, 2, true
+	case gestures.ScrollEvent:
+		return GestureDrag, 2, true
+	case gestures.SwipeEvent:
+		return GestureSwipe, e.Fingers, true
+	default:
+		return "", 0, false
+	}
+}
+
 type TrackpadController struct {
+	cfg        Config
 	state      *ControllerState
 	blocking   bool
 	lastUpdate time.Time
 }
 
-func NewTrackpadController() *TrackpadController {
+func NewTrackpadController(cfg Config) *TrackpadController {
+	if cfg == (Config{}) {
+		cfg = DefaultConfig()
+	}
 	return &TrackpadController{
+		cfg: cfg,
 		state: &ControllerState{
 			ActiveTouches: make(map[int]Touch),
 			CenterX:       0.5,
@@ -139,23 +176,35 @@ func NewTrackpadController() *TrackpadController {
 	}
 }
 
+// ProcessTouch feeds one touch into the controller. Palms (per Config's
+// size/majorAxis/eccentricity thresholds) are rejected outright; thumbs
+// near the bottom edge are kept in ActiveTouches for click detection but
+// don't move the joystick.
 func (tc *TrackpadController) ProcessTouch(touch Touch) {
-	tc.state.ActiveTouches[touch.ID] = touch
-	
+	isPalm, isThumb := tc.cfg.classify(touch)
+	if isPalm {
+		return
+	}
+
+	tc.state.ActiveTouches[touch.Identifier] = touch
+	if isThumb {
+		return
+	}
+
 	dx := touch.X - tc.state.CenterX
 	dy := touch.Y - tc.state.CenterY
 	distance := math.Sqrt(dx*dx + dy*dy)
-	
+
 	if distance > tc.state.Radius {
 		dx = dx / distance * tc.state.Radius
 		dy = dy / distance * tc.state.Radius
 	}
-	
-	joystickX := dx / tc.state.Radius
-	joystickY := dy / tc.state.Radius
-	
+
+	joystickX := tc.cfg.accelerate(dx / tc.state.Radius)
+	joystickY := tc.cfg.accelerate(dy / tc.state.Radius)
+
 	fmt.Printf("Controller: Touch ID=%d → Joystick (%.2f, %.2f) Size=%.2f\n",
-		touch.ID, joystickX, joystickY, touch.Size)
+		touch.Identifier, joystickX, joystickY, touch.Size)
 }
 
 func (tc *TrackpadController) RemoveTouch(id int) {
@@ -176,7 +225,7 @@ func (tc *TrackpadController) SetBlocking(block bool) {
 
 func (tc *TrackpadController) GetGestureType() string {
 	numTouches := len(tc.state.ActiveTouches)
-	
+
 	switch numTouches {
 	case 0:
 		return "none"
@@ -191,42 +240,37 @@ func (tc *TrackpadController) GetGestureType() string {
 	}
 }
 
-//export goEventCallback
-func goEventCallback(eventType C.int, x C.double, y C.double) {
-	// Events are blocked in the C callback
-}
-
-//export goMultitouchCallback
-func goMultitouchCallback(device C.int, touches *C.MTTouch, numTouches C.int, timestamp C.double, frame C.int) C.int {
+// processTouchFrame feeds one decoded multitouch frame into the controller
+// and gesture recognizer. Device capture and frame decoding live in
+// MultitouchCapture (multitouch_raw_darwin.go); this is what the combined
+// example's goMultitouchCallback did before the two were merged onto one
+// shared capture path.
+func processTouchFrame(frame TouchFrame) {
 	if controller == nil {
-		return 0
+		return
 	}
 
-	// Handle no touches - unblock events to allow normal mouse movement
-	if numTouches == 0 {
+	if len(frame.Touches) == 0 {
+		// No touches - unblock events to allow normal mouse movement
 		controller.SetBlocking(false)
-		return 0
+		return
 	}
 
-	touchSlice := (*[100]C.MTTouch)(unsafe.Pointer(touches))[:numTouches:numTouches]
-
-	currentTouches := make(map[int]bool)
-
-	for i := 0; i < int(numTouches); i++ {
-		mt := touchSlice[i]
+	currentTouches := make(map[int]bool, len(frame.Touches))
+	contacts := make([]gestures.Contact, 0, len(frame.Touches))
 
-		touch := Touch{
-			ID:    int(mt.identifier),
-			X:     float64(mt.normalized.position.x),
-			Y:     float64(mt.normalized.position.y),
-			VelX:  float64(mt.normalized.velocity.x),
-			VelY:  float64(mt.normalized.velocity.y),
-			Size:  float64(mt.size),
-			Angle: float64(mt.angle) * 180.0 / math.Pi,
-		}
-
-		currentTouches[touch.ID] = true
+	for _, touch := range frame.Touches {
+		currentTouches[touch.Identifier] = true
 		controller.ProcessTouch(touch)
+
+		contacts = append(contacts, gestures.Contact{
+			ID:        touch.Identifier,
+			X:         touch.X,
+			Y:         touch.Y,
+			Size:      touch.Size,
+			MajorAxis: touch.MajorAxis,
+			MinorAxis: touch.MinorAxis,
+		})
 	}
 
 	for id := range controller.state.ActiveTouches {
@@ -235,15 +279,29 @@ func goMultitouchCallback(device C.int, touches *C.MTTouch, numTouches C.int, ti
 		}
 	}
 
-	// Block events only when 2+ fingers are touching (multitouch)
-	// Allow events with single finger so mouse moves normally
-	if int(numTouches) >= 2 {
-		controller.SetBlocking(true)
-	} else {
-		controller.SetBlocking(false)
+	recognizer.Feed(gestures.HWState{Timestamp: frame.Timestamp, Contacts: contacts})
+
+	// Drain whatever the recognizer published for this frame. The native
+	// event for a blocked gesture is already suppressed below (blockFlag);
+	// if it identified a pinch/drag/swipe and a binding matches it, inject
+	// a synthetic replacement instead of just dropping it.
+drainEvents:
+	for {
+		select {
+		case ev := <-recognizer.Events():
+			if virtualInput != nil {
+				if kind, fingers, ok := gestureKindFor(ev); ok {
+					virtualInput.HandleGesture(kind, fingers)
+				}
+			}
+		default:
+			break drainEvents
+		}
 	}
 
-	return 0
+	// Block events only when 2+ fingers are touching (multitouch)
+	// Allow events with single finger so mouse moves normally
+	controller.SetBlocking(len(frame.Touches) >= 2)
 }
 
 func setupEventCapture() error {
@@ -255,26 +313,33 @@ func setupEventCapture() error {
 		C.free(unsafe.Pointer(blockFlag))
 		return fmt.Errorf("failed to create event tap - check accessibility permissions")
 	}
-	
+
 	runLoopSrc = C.CFMachPortCreateRunLoopSource(
 		C.kCFAllocatorDefault,
 		eventTap,
 		0,
 	)
-	
+
 	C.CFRunLoopAddSource(
 		C.CFRunLoopGetCurrent(),
 		runLoopSrc,
 		C.kCFRunLoopCommonModes,
 	)
-	
+
 	C.CGEventTapEnable(eventTap, C.bool(true))
-	
-	mtDevice = C.setupMultitouch()
-	if uintptr(unsafe.Pointer(mtDevice)) == 0 {
-		return fmt.Errorf("failed to initialize multitouch")
+
+	mc, err := NewMultitouchCapture()
+	if err != nil {
+		return fmt.Errorf("failed to initialize multitouch: %w", err)
 	}
-	
+	multitouch = mc
+
+	go func() {
+		for frame := range multitouch.GetTouchChannel() {
+			processTouchFrame(frame)
+		}
+	}()
+
 	return nil
 }
 
@@ -286,8 +351,8 @@ func cleanup() {
 	if uintptr(unsafe.Pointer(runLoopSrc)) != 0 {
 		C.CFRelease(C.CFTypeRef(runLoopSrc))
 	}
-	if uintptr(unsafe.Pointer(mtDevice)) != 0 {
-		C.MTDeviceStop(mtDevice)
+	if multitouch != nil {
+		multitouch.Stop()
 	}
 	if blockFlag != nil {
 		C.free(unsafe.Pointer(blockFlag))
@@ -297,11 +362,11 @@ func cleanup() {
 func printStatus(tc *TrackpadController) {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
-	
+
 	for range ticker.C {
 		gesture := tc.GetGestureType()
 		numTouches := len(tc.state.ActiveTouches)
-		
+
 		if numTouches > 0 {
 			fmt.Printf("\r[Status] Gesture: %-15s | Active touches: %d | Blocking: %v",
 				gesture, numTouches, tc.blocking)
@@ -309,7 +374,15 @@ func printStatus(tc *TrackpadController) {
 	}
 }
 
-func main() {
+// runJoystick implements `capture joystick [-bindings file]`: captures raw
+// touch data AND blocks OS events, turning the trackpad into a joystick.
+func runJoystick(args []string) error {
+	fs := flag.NewFlagSet("joystick", flag.ContinueOnError)
+	bindingsPath := fs.String("bindings", "", "path to a JSON GestureBinding config for remapping blocked gestures")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
 	fmt.Println("═══════════════════════════════════════════")
 	fmt.Println("  Trackpad Controller - Combined Example")
 	fmt.Println("═══════════════════════════════════════════")
@@ -317,14 +390,23 @@ func main() {
 	fmt.Println("This captures raw touch data AND blocks OS events")
 	fmt.Println("Use your trackpad as a controller for your software!")
 	fmt.Println()
-	
-	controller = NewTrackpadController()
-	
+
+	if *bindingsPath != "" {
+		bindings, err := LoadGestureBindings(*bindingsPath)
+		if err != nil {
+			return fmt.Errorf("failed to load gesture bindings: %w", err)
+		}
+		virtualInput = NewVirtualInputController(bindings)
+		fmt.Printf("✓ Loaded %d gesture binding(s) from %s\n", len(bindings), *bindingsPath)
+	}
+
+	controller = NewTrackpadController(DefaultConfig())
+
 	if err := setupEventCapture(); err != nil {
-		log.Fatalf("Setup failed: %v", err)
+		return fmt.Errorf("setup failed: %w", err)
 	}
 	defer cleanup()
-	
+
 	controller.SetBlocking(false)
 
 	fmt.Println("✓ Event capture initialized")
@@ -336,18 +418,18 @@ func main() {
 	fmt.Println("  • Two+ fingers = cursor halts")
 	fmt.Println("Press Ctrl+C to exit and restore normal operation")
 	fmt.Println()
-	
+
 	go printStatus(controller)
-	
+
 	go func() {
 		C.CFRunLoopRun()
 	}()
-	
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
-	
+
 	fmt.Println("\n\nShutting down and restoring normal trackpad operation...")
 	time.Sleep(500 * time.Millisecond)
+	return nil
 }
-
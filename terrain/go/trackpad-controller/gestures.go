@@ -0,0 +1,155 @@
+package main
+
+// GesturePhase mirrors AppKit's Began/Changed/Ended convention for
+// continuous gestures (NSGestureRecognizer.State, NSEvent phases).
+type GesturePhase int
+
+const (
+	PhaseBegan GesturePhase = iota
+	PhaseChanged
+	PhaseEnded
+)
+
+func (p GesturePhase) String() string {
+	switch p {
+	case PhaseBegan:
+		return "Began"
+	case PhaseChanged:
+		return "Changed"
+	case PhaseEnded:
+		return "Ended"
+	default:
+		return "Unknown"
+	}
+}
+
+// PinchEvent reports a two-finger magnify gesture; Scale is the cumulative
+// zoom factor for the gesture so far (1.0 = no change).
+type PinchEvent struct {
+	Scale float64
+	Phase GesturePhase
+}
+
+// RotateEvent reports a two-finger rotation gesture in radians, positive
+// counter-clockwise.
+type RotateEvent struct {
+	Radians float64
+	Phase   GesturePhase
+}
+
+// SwipeEvent reports a discrete multi-finger swipe; DX/DY are in the
+// -1..1 range AppKit uses for NSEvent swipe deltas.
+type SwipeEvent struct {
+	DX, DY  float64
+	Fingers int
+}
+
+// TapEvent reports a multi-finger tap.
+type TapEvent struct {
+	Fingers int
+}
+
+// GestureThresholds tunes how sensitive a GestureRecognizer is; zero values
+// fall back to sane defaults in NewGestureRecognizer.
+type GestureThresholds struct {
+	MinScaleDelta      float64 // minimum |scale-1.0| before a PinchEvent fires
+	MinRotationRadians float64 // minimum rotation before a RotateEvent fires
+	SwipeVelocityFloor float64 // minimum net centroid speed before a SwipeEvent fires
+}
+
+func defaultThresholds() GestureThresholds {
+	return GestureThresholds{
+		MinScaleDelta:      0.02,
+		MinRotationRadians: 0.05,
+		SwipeVelocityFloor: 0.1,
+	}
+}
+
+// GestureRecognizer consumes the raw NSEvent-derived gesture stream (see
+// gestures_darwin.go) and republishes it as typed events on GestureChannel,
+// applying configurable thresholds so jitter doesn't produce noise events.
+// When Consume is true, recognized gestures are swallowed rather than
+// forwarded further, mirroring TrackpadCapture's blockEvents flag.
+type GestureRecognizer struct {
+	thresholds GestureThresholds
+	consume    bool
+	ch         chan interface{}
+
+	lastScale   float64
+	lastRadians float64
+}
+
+// NewGestureRecognizer builds a recognizer with the given thresholds; pass
+// a zero-value GestureThresholds to use the defaults.
+func NewGestureRecognizer(thresholds GestureThresholds) *GestureRecognizer {
+	if thresholds == (GestureThresholds{}) {
+		thresholds = defaultThresholds()
+	}
+	return &GestureRecognizer{
+		thresholds: thresholds,
+		ch:         make(chan interface{}, 100),
+		lastScale:  1.0,
+	}
+}
+
+// GestureChannel returns the channel recognized gesture events are
+// published on, alongside (not instead of) a capture's raw Events().
+func (g *GestureRecognizer) GestureChannel() <-chan interface{} { return g.ch }
+
+// SetConsume toggles whether recognized gestures should be treated as
+// consumed by the application rather than forwarded to the OS.
+func (g *GestureRecognizer) SetConsume(consume bool) { g.consume = consume }
+
+func (g *GestureRecognizer) Consuming() bool { return g.consume }
+
+func (g *GestureRecognizer) publish(ev interface{}) {
+	select {
+	case g.ch <- ev:
+	default:
+	}
+}
+
+// OnMagnify feeds a raw NSEventTypeMagnify delta (cumulative scale) into the
+// recognizer; it only publishes once the change exceeds MinScaleDelta.
+func (g *GestureRecognizer) OnMagnify(scale float64, phase GesturePhase) {
+	if phase == PhaseBegan {
+		g.lastScale = 1.0
+	}
+	if absf(scale-g.lastScale) >= g.thresholds.MinScaleDelta || phase == PhaseEnded {
+		g.publish(PinchEvent{Scale: scale, Phase: phase})
+		g.lastScale = scale
+	}
+}
+
+// OnRotate feeds a raw NSEventTypeRotate delta (cumulative radians) into the
+// recognizer; it only publishes once the change exceeds MinRotationRadians.
+func (g *GestureRecognizer) OnRotate(radians float64, phase GesturePhase) {
+	if phase == PhaseBegan {
+		g.lastRadians = 0
+	}
+	if absf(radians-g.lastRadians) >= g.thresholds.MinRotationRadians || phase == PhaseEnded {
+		g.publish(RotateEvent{Radians: radians, Phase: phase})
+		g.lastRadians = radians
+	}
+}
+
+// OnSwipe feeds a raw NSEventTypeSwipe delta into the recognizer, dropping
+// swipes slower than SwipeVelocityFloor.
+func (g *GestureRecognizer) OnSwipe(dx, dy float64, fingers int) {
+	if absf(dx)+absf(dy) < g.thresholds.SwipeVelocityFloor {
+		return
+	}
+	g.publish(SwipeEvent{DX: dx, DY: dy, Fingers: fingers})
+}
+
+// OnTap feeds a raw multi-finger tap into the recognizer.
+func (g *GestureRecognizer) OnTap(fingers int) {
+	g.publish(TapEvent{Fingers: fingers})
+}
+
+func absf(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,205 @@
+package gestures
+
+import "math"
+
+// ScrollEvent reports two-finger scrolling; DX/DY are the scaled net
+// finger travel since the last frame.
+type ScrollEvent struct {
+	DX, DY float64
+}
+
+// PinchEvent reports a change in the mean finger-to-centroid distance;
+// Scale is the ratio of that distance to its value when the gesture
+// began (1.0 = no change).
+type PinchEvent struct {
+	Scale float64
+}
+
+// RotateEvent reports the mean angular change of finger vectors around
+// the centroid since the gesture began, in radians, positive
+// counter-clockwise.
+type RotateEvent struct {
+	Radians float64
+}
+
+// SwipeEvent reports a thresholded net centroid motion over Fingers
+// touches.
+type SwipeEvent struct {
+	DX, DY  float64
+	Fingers int
+}
+
+// TapEvent reports a touch (or multi-finger touch) that lifted again
+// within TapMaxAge frames having travelled less than TapMaxTravel.
+type TapEvent struct {
+	Fingers int
+}
+
+// Config tunes the gesture math, named after the Chromium/mtrack
+// vscroll_fraction-style tunables it mirrors.
+type Config struct {
+	Thresholds
+
+	VScrollFraction    float64 // scroll DY scale per unit of finger travel
+	HScrollFraction    float64 // scroll DX scale per unit of finger travel
+	MinScaleDelta      float64 // minimum |scale-1.0| before a PinchEvent fires
+	MinRotationRadians float64 // minimum rotation before a RotateEvent fires
+	SwipeMinDistance   float64 // minimum net centroid motion before a SwipeEvent fires
+	TapMaxTravel       float64 // maximum travel for a lift to count as a tap
+	TapMaxAge          int     // maximum frame age for a lift to count as a tap
+}
+
+// DefaultConfig returns thresholds that pass through the package's own
+// DefaultThresholds for palm/thumb classification.
+func DefaultConfig() Config {
+	return Config{
+		Thresholds:         DefaultThresholds(),
+		VScrollFraction:    0.005,
+		HScrollFraction:    0.005,
+		MinScaleDelta:      0.02,
+		MinRotationRadians: 0.05,
+		SwipeMinDistance:   0.15,
+		TapMaxTravel:       0.02,
+		TapMaxAge:          3,
+	}
+}
+
+// gestureState is the centroid/spread/angle snapshot a gesture compares
+// the current frame against; it resets whenever the active finger count
+// changes.
+type gestureState struct {
+	fingers  int
+	centroid struct{ x, y float64 }
+	spread   float64
+	angle    float64
+}
+
+// Recognizer consumes successive HWState frames — via hwstate's mtstate
+// tracking of per-finger lifecycle and palm/thumb classification — and
+// emits typed gesture events on Events(). Palms and thumbs are tracked
+// by mtstate but excluded from the gesture math below.
+type Recognizer struct {
+	cfg   Config
+	state *mtstate
+	ch    chan interface{}
+
+	have bool
+	prev gestureState
+}
+
+// NewRecognizer builds a recognizer with cfg; pass DefaultConfig() for
+// sane defaults.
+func NewRecognizer(cfg Config) *Recognizer {
+	return &Recognizer{
+		cfg:   cfg,
+		state: newMTState(cfg.Thresholds),
+		ch:    make(chan interface{}, 100),
+	}
+}
+
+// Events returns the channel recognized gesture events are published on.
+func (r *Recognizer) Events() <-chan interface{} { return r.ch }
+
+func (r *Recognizer) publish(ev interface{}) {
+	select {
+	case r.ch <- ev:
+	default:
+	}
+}
+
+// Feed folds one HWState frame into the recognizer, publishing whatever
+// gesture events the transition from the previous frame implies.
+func (r *Recognizer) Feed(hw HWState) {
+	touches := r.state.update(hw)
+
+	var active []*Touch
+	var lifted []*Touch
+	for _, t := range touches {
+		if t.Phase == touchEnded {
+			lifted = append(lifted, t)
+			continue
+		}
+		if t.Class == ClassFinger {
+			active = append(active, t)
+		}
+	}
+
+	for _, t := range lifted {
+		if t.Class == ClassFinger && t.Travel <= r.cfg.TapMaxTravel && t.Age <= r.cfg.TapMaxAge {
+			r.publish(TapEvent{Fingers: 1})
+		}
+	}
+
+	cur := computeState(active)
+	if !r.have || cur.fingers != r.prev.fingers {
+		r.prev = cur
+		r.have = cur.fingers > 0
+		return
+	}
+
+	switch cur.fingers {
+	case 2:
+		dx := (cur.centroid.x - r.prev.centroid.x) * r.cfg.HScrollFraction
+		dy := (cur.centroid.y - r.prev.centroid.y) * r.cfg.VScrollFraction
+		if dx != 0 || dy != 0 {
+			r.publish(ScrollEvent{DX: dx, DY: dy})
+		}
+		if r.prev.spread > 0 {
+			scale := cur.spread / r.prev.spread
+			if math.Abs(scale-1.0) >= r.cfg.MinScaleDelta {
+				r.publish(PinchEvent{Scale: scale})
+			}
+		}
+		dtheta := angleDelta(r.prev.angle, cur.angle)
+		if math.Abs(dtheta) >= r.cfg.MinRotationRadians {
+			r.publish(RotateEvent{Radians: dtheta})
+		}
+	case 3, 4:
+		dx, dy := cur.centroid.x-r.prev.centroid.x, cur.centroid.y-r.prev.centroid.y
+		if math.Hypot(dx, dy) >= r.cfg.SwipeMinDistance {
+			r.publish(SwipeEvent{DX: dx, DY: dy, Fingers: cur.fingers})
+		}
+	}
+
+	r.prev = cur
+}
+
+// computeState derives the centroid, mean finger-to-centroid distance
+// (spread), and mean angle of finger vectors around the centroid for
+// the active finger set.
+func computeState(active []*Touch) gestureState {
+	var s gestureState
+	s.fingers = len(active)
+	if s.fingers == 0 {
+		return s
+	}
+	for _, t := range active {
+		s.centroid.x += t.X
+		s.centroid.y += t.Y
+	}
+	s.centroid.x /= float64(s.fingers)
+	s.centroid.y /= float64(s.fingers)
+
+	var sumDist, sumAngle float64
+	for _, t := range active {
+		dx, dy := t.X-s.centroid.x, t.Y-s.centroid.y
+		sumDist += math.Hypot(dx, dy)
+		sumAngle += math.Atan2(dy, dx)
+	}
+	s.spread = sumDist / float64(s.fingers)
+	s.angle = sumAngle / float64(s.fingers)
+	return s
+}
+
+// angleDelta returns b-a wrapped into (-pi, pi] so a rotation crossing
+// the +/-pi seam doesn't report a spurious near-2*pi jump.
+func angleDelta(a, b float64) float64 {
+	d := b - a
+	for d > math.Pi {
+		d -= 2 * math.Pi
+	}
+	for d <= -math.Pi {
+		d += 2 * math.Pi
+	}
+	return d
+}
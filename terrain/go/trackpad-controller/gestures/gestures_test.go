@@ -0,0 +1,92 @@
+package gestures
+
+import "testing"
+
+func drain(t *testing.T, ch <-chan interface{}) []interface{} {
+	t.Helper()
+	var evs []interface{}
+	for {
+		select {
+		case ev := <-ch:
+			evs = append(evs, ev)
+		default:
+			return evs
+		}
+	}
+}
+
+func TestRecognizerPinch(t *testing.T) {
+	r := NewRecognizer(DefaultConfig())
+
+	r.Feed(HWState{Contacts: []Contact{
+		{ID: 0, X: 0.4, Y: 0.5}, {ID: 1, X: 0.6, Y: 0.5},
+	}})
+	drain(t, r.Events())
+
+	r.Feed(HWState{Contacts: []Contact{
+		{ID: 0, X: 0.3, Y: 0.5}, {ID: 1, X: 0.7, Y: 0.5},
+	}})
+	evs := drain(t, r.Events())
+
+	var got PinchEvent
+	found := false
+	for _, ev := range evs {
+		if p, ok := ev.(PinchEvent); ok {
+			got, found = p, true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a PinchEvent, got %+v", evs)
+	}
+	if got.Scale <= 1.0 {
+		t.Fatalf("expected scale > 1.0 for fingers moving apart, got %v", got.Scale)
+	}
+}
+
+func TestRecognizerPalmExcludedFromFingerCount(t *testing.T) {
+	r := NewRecognizer(DefaultConfig())
+
+	r.Feed(HWState{Contacts: []Contact{
+		{ID: 0, X: 0.4, Y: 0.5},
+		{ID: 1, X: 0.6, Y: 0.5},
+		{ID: 2, X: 0.5, Y: 0.9, Size: 20},
+	}})
+	touches := r.state.touches
+	if touches[2].Class != ClassPalm {
+		t.Fatalf("expected contact 2 to classify as a palm, got %v", touches[2].Class)
+	}
+}
+
+func TestRecognizerTapOnQuickLift(t *testing.T) {
+	r := NewRecognizer(DefaultConfig())
+
+	r.Feed(HWState{Contacts: []Contact{{ID: 0, X: 0.5, Y: 0.5}}})
+	drain(t, r.Events())
+	r.Feed(HWState{})
+	evs := drain(t, r.Events())
+
+	found := false
+	for _, ev := range evs {
+		if _, ok := ev.(TapEvent); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a TapEvent, got %+v", evs)
+	}
+}
+
+func TestRecognizerPalmLiftDoesNotTap(t *testing.T) {
+	r := NewRecognizer(DefaultConfig())
+
+	r.Feed(HWState{Contacts: []Contact{{ID: 0, X: 0.5, Y: 0.9, Size: 20}}})
+	drain(t, r.Events())
+	r.Feed(HWState{})
+	evs := drain(t, r.Events())
+
+	for _, ev := range evs {
+		if _, ok := ev.(TapEvent); ok {
+			t.Fatalf("expected no TapEvent for a lifted palm, got %+v", evs)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package gestures recognizes high-level touch gestures from a raw
+// multitouch contact stream, following xf86-input-mtrack's three-layer
+// model: hwstate (this file) is the raw per-frame snapshot, mtstate.go
+// tracks per-finger lifecycle and palm/thumb classification across
+// frames, and gestures.go turns the deltas between frames into typed
+// gesture events.
+package gestures
+
+// Contact is one raw touch as reported in a single frame — a package's
+// own view of a driver's hardware state, independent of any specific
+// capture backend (MultitouchSupport, evdev, etc.) so callers adapt
+// their own touch type into this one.
+type Contact struct {
+	ID        int
+	X, Y      float64
+	Size      float64
+	MajorAxis float64
+	MinorAxis float64
+}
+
+// HWState is one frame's full set of raw contacts, timestamped so the
+// mtstate layer can compute travel and age between frames.
+type HWState struct {
+	Timestamp float64
+	Contacts  []Contact
+}
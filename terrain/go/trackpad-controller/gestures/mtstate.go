@@ -0,0 +1,119 @@
+package gestures
+
+import "math"
+
+// touchPhase mirrors mtrack's mtstate lifecycle for a single finger
+// across frames.
+type touchPhase int
+
+const (
+	touchNew touchPhase = iota
+	touchActive
+	touchEnded
+)
+
+// Classification marks a touch as a normal finger, a palm, or a thumb so
+// the gesture layer can exclude it from scroll/pinch/rotate math without
+// losing it entirely (a thumb may still count for click detection).
+type Classification int
+
+const (
+	ClassFinger Classification = iota
+	ClassPalm
+	ClassThumb
+)
+
+func (c Classification) String() string {
+	switch c {
+	case ClassPalm:
+		return "Palm"
+	case ClassThumb:
+		return "Thumb"
+	default:
+		return "Finger"
+	}
+}
+
+// Thresholds tunes palm/thumb classification, named after mtrack's
+// palm_size/palm_width options.
+type Thresholds struct {
+	PalmSizeMin          float64 // Size at or above this is a palm
+	PalmMajorAxisMin     float64 // MajorAxis at or above this is a palm
+	ThumbMajorMinorRatio float64 // MajorAxis/MinorAxis at or above this is a thumb
+}
+
+// DefaultThresholds returns thresholds tuned for MultitouchSupport's
+// normalized (0..1-ish) size/axis units.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		PalmSizeMin:          15.0,
+		PalmMajorAxisMin:     12.0,
+		ThumbMajorMinorRatio: 2.2,
+	}
+}
+
+// Touch is one finger's persistent state across frames: its current
+// position, cumulative travel since it first touched down, age in
+// frames, and classification.
+type Touch struct {
+	ID      int
+	Phase   touchPhase
+	Class   Classification
+	X, Y    float64
+	OriginX float64
+	OriginY float64
+	Travel  float64
+	Age     int
+}
+
+// mtstate maintains live Touch entries across frames, keyed by Contact
+// ID, classifying and aging each one as new HWState snapshots arrive.
+type mtstate struct {
+	thresholds Thresholds
+	touches    map[int]*Touch
+}
+
+func newMTState(th Thresholds) *mtstate {
+	return &mtstate{thresholds: th, touches: make(map[int]*Touch)}
+}
+
+// update folds one HWState into the tracker and returns every touch
+// live this frame, including ones that just ended (Phase == touchEnded),
+// so the gesture layer sees a lift exactly once.
+func (m *mtstate) update(hw HWState) []*Touch {
+	seen := make(map[int]bool, len(hw.Contacts))
+	var live []*Touch
+	for _, c := range hw.Contacts {
+		seen[c.ID] = true
+		t, ok := m.touches[c.ID]
+		if !ok {
+			t = &Touch{ID: c.ID, Phase: touchNew, X: c.X, Y: c.Y, OriginX: c.X, OriginY: c.Y}
+			m.touches[c.ID] = t
+		} else {
+			t.Travel += math.Hypot(c.X-t.X, c.Y-t.Y)
+			t.X, t.Y = c.X, c.Y
+			t.Phase = touchActive
+			t.Age++
+		}
+		t.Class = classify(c, m.thresholds)
+		live = append(live, t)
+	}
+	for id, t := range m.touches {
+		if !seen[id] {
+			t.Phase = touchEnded
+			live = append(live, t)
+			delete(m.touches, id)
+		}
+	}
+	return live
+}
+
+func classify(c Contact, th Thresholds) Classification {
+	if c.Size >= th.PalmSizeMin || c.MajorAxis >= th.PalmMajorAxisMin {
+		return ClassPalm
+	}
+	if c.MinorAxis > 0 && c.MajorAxis/c.MinorAxis >= th.ThumbMajorMinorRatio {
+		return ClassThumb
+	}
+	return ClassFinger
+}
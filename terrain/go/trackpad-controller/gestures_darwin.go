@@ -0,0 +1,123 @@
+//go:build darwin
+
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit -framework CoreFoundation
+#import <AppKit/AppKit.h>
+
+extern void goMagnifyCallback(double scale, int phase);
+extern void goRotateCallback(double radians, int phase);
+extern void goSwipeCallback(double dx, double dy);
+extern void goGestureCallback(void);
+
+static id magnifyMonitor = nil;
+static id rotateMonitor = nil;
+static id swipeMonitor = nil;
+static id gestureMonitor = nil;
+
+static int phaseFromNSEvent(NSEvent *event) {
+    switch (event.phase) {
+        case NSEventPhaseBegan:
+            return 0;
+        case NSEventPhaseEnded:
+        case NSEventPhaseCancelled:
+            return 2;
+        default:
+            return 1;
+    }
+}
+
+void installGestureMonitors(void) {
+    NSEventMask magnifyMask = NSEventMaskMagnify;
+    NSEventMask rotateMask = NSEventMaskRotate;
+    NSEventMask swipeMask = NSEventMaskSwipe;
+    NSEventMask gestureMask = NSEventMaskGesture;
+
+    magnifyMonitor = [NSEvent addLocalMonitorForEventsMatchingMask:magnifyMask handler:^NSEvent *(NSEvent *event) {
+        goMagnifyCallback(event.magnification + 1.0, phaseFromNSEvent(event));
+        return event;
+    }];
+    rotateMonitor = [NSEvent addLocalMonitorForEventsMatchingMask:rotateMask handler:^NSEvent *(NSEvent *event) {
+        goRotateCallback(event.rotation * M_PI / 180.0, phaseFromNSEvent(event));
+        return event;
+    }];
+    swipeMonitor = [NSEvent addLocalMonitorForEventsMatchingMask:swipeMask handler:^NSEvent *(NSEvent *event) {
+        goSwipeCallback(event.deltaX, event.deltaY);
+        return event;
+    }];
+    gestureMonitor = [NSEvent addLocalMonitorForEventsMatchingMask:gestureMask handler:^NSEvent *(NSEvent *event) {
+        goGestureCallback();
+        return event;
+    }];
+}
+
+void removeGestureMonitors(void) {
+    if (magnifyMonitor) { [NSEvent removeMonitor:magnifyMonitor]; magnifyMonitor = nil; }
+    if (rotateMonitor) { [NSEvent removeMonitor:rotateMonitor]; rotateMonitor = nil; }
+    if (swipeMonitor) { [NSEvent removeMonitor:swipeMonitor]; swipeMonitor = nil; }
+    if (gestureMonitor) { [NSEvent removeMonitor:gestureMonitor]; gestureMonitor = nil; }
+}
+*/
+import "C"
+
+// activeGestures mirrors activeCapture: the NSEvent monitor callbacks have
+// no way to carry a receiver pointer, so they're fanned into whichever
+// *GestureRecognizer is currently installed.
+var activeGestures *GestureRecognizer
+
+//export goMagnifyCallback
+func goMagnifyCallback(scale C.double, phase C.int) {
+	if activeGestures == nil {
+		return
+	}
+	activeGestures.OnMagnify(float64(scale), GesturePhase(phase))
+}
+
+//export goRotateCallback
+func goRotateCallback(radians C.double, phase C.int) {
+	if activeGestures == nil {
+		return
+	}
+	activeGestures.OnRotate(float64(radians), GesturePhase(phase))
+}
+
+//export goSwipeCallback
+func goSwipeCallback(dx, dy C.double) {
+	if activeGestures == nil {
+		return
+	}
+	activeGestures.OnSwipe(float64(dx), float64(dy), 3)
+}
+
+//export goGestureCallback
+func goGestureCallback() {
+	if activeGestures == nil {
+		return
+	}
+	activeGestures.OnTap(2)
+}
+
+// Gestures returns the capture's GestureRecognizer, installing NSEvent
+// monitors for Magnify/Rotate/Swipe/Gesture the first time it's called.
+// Consumers read typed events off Gestures().GestureChannel() independently
+// of the raw Events() stream.
+func (tc *TrackpadCapture) Gestures() *GestureRecognizer {
+	if tc.gestures == nil {
+		tc.gestures = NewGestureRecognizer(GestureThresholds{})
+	}
+	return tc.gestures
+}
+
+func (tc *TrackpadCapture) startGestureMonitors() {
+	activeGestures = tc.Gestures()
+	C.installGestureMonitors()
+}
+
+func (tc *TrackpadCapture) stopGestureMonitors() {
+	C.removeGestureMonitors()
+	if activeGestures == tc.gestures {
+		activeGestures = nil
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import "sync"
+
+// EventType enumerates the pointer/trackpad events an InputCapture backend
+// can report, independent of how the platform actually delivers them.
+type EventType int
+
+const (
+	EventMouseMoved EventType = iota
+	EventLeftMouseDown
+	EventLeftMouseUp
+	EventRightMouseDown
+	EventRightMouseUp
+	EventLeftMouseDragged
+	EventRightMouseDragged
+	EventScrollWheel
+	EventGesture
+	EventOther
+)
+
+func (e EventType) String() string {
+	names := []string{
+		"MouseMoved", "LeftMouseDown", "LeftMouseUp",
+		"RightMouseDown", "RightMouseUp",
+		"LeftMouseDragged", "RightMouseDragged",
+		"ScrollWheel", "Gesture", "Other",
+	}
+	if int(e) < len(names) {
+		return names[e]
+	}
+	return "Unknown"
+}
+
+// TrackpadEvent is the backend-agnostic event value delivered on an
+// InputCapture's Events() channel.
+type TrackpadEvent struct {
+	Type    EventType
+	X       float64
+	Y       float64
+	Buttons int
+}
+
+// Options configures an InputCapture backend at construction time.
+type Options struct {
+	// BlockEvents requests that the backend suppress events from reaching
+	// the OS/window manager rather than just observing them, where the
+	// platform supports it.
+	BlockEvents bool
+}
+
+// InputCapture is the cross-platform abstraction every backend implements:
+// CGEventTap on macOS, XInput2/XRecord on Linux, and a low-level mouse hook
+// on Windows, following the same split fzf uses for its terminal layer
+// (tui/ncurses.go, tui/termbox.go, tui/tcell.go) under build tags.
+type InputCapture interface {
+	Start() error
+	Stop()
+	SetBlocking(block bool)
+	Events() <-chan TrackpadEvent
+}
+
+// NewInputCapture selects and constructs the InputCapture backend compiled
+// in for the current platform; see trackpad_capture_darwin.go,
+// input_capture_linux.go, and input_capture_windows.go.
+func NewInputCapture(opts Options) (InputCapture, error) {
+	return newPlatformInputCapture(opts)
+}
+
+// baseCapture implements the bookkeeping shared by every real backend: an
+// event channel and a blocking flag guarded by a mutex.
+type baseCapture struct {
+	events  chan TrackpadEvent
+	mu      sync.Mutex
+	blocked bool
+}
+
+func newBaseCapture(blockEvents bool) baseCapture {
+	return baseCapture{
+		events:  make(chan TrackpadEvent, 100),
+		blocked: blockEvents,
+	}
+}
+
+func (b *baseCapture) Events() <-chan TrackpadEvent { return b.events }
+
+func (b *baseCapture) SetBlocking(block bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blocked = block
+}
+
+func (b *baseCapture) isBlocked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocked
+}
+
+func (b *baseCapture) emit(ev TrackpadEvent) {
+	select {
+	case b.events <- ev:
+	default:
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+// fakeCapture is an in-memory InputCapture used by tests so behavior can be
+// exercised without OS accessibility permissions or a real trackpad.
+type fakeCapture struct {
+	baseCapture
+	started bool
+	stopped bool
+}
+
+func newFakeCapture(opts Options) *fakeCapture {
+	return &fakeCapture{baseCapture: newBaseCapture(opts.BlockEvents)}
+}
+
+func (f *fakeCapture) Start() error {
+	f.started = true
+	return nil
+}
+
+func (f *fakeCapture) Stop() {
+	f.stopped = true
+}
+
+// inject feeds a synthetic event into the capture, as if the OS had
+// delivered it.
+func (f *fakeCapture) inject(ev TrackpadEvent) {
+	f.emit(ev)
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestFakeCaptureStartStop(t *testing.T) {
+	f := newFakeCapture(Options{BlockEvents: true})
+	if err := f.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if !f.started {
+		t.Fatal("expected started to be true")
+	}
+	f.Stop()
+	if !f.stopped {
+		t.Fatal("expected stopped to be true")
+	}
+}
+
+func TestFakeCaptureEmitsEvents(t *testing.T) {
+	f := newFakeCapture(Options{})
+	f.inject(TrackpadEvent{Type: EventLeftMouseDown, X: 1, Y: 2})
+
+	select {
+	case ev := <-f.Events():
+		if ev.Type != EventLeftMouseDown || ev.X != 1 || ev.Y != 2 {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("expected an event on the channel")
+	}
+}
+
+func TestFakeCaptureSetBlocking(t *testing.T) {
+	f := newFakeCapture(Options{BlockEvents: false})
+	if f.isBlocked() {
+		t.Fatal("expected not blocked initially")
+	}
+	f.SetBlocking(true)
+	if !f.isBlocked() {
+		t.Fatal("expected blocked after SetBlocking(true)")
+	}
+}
@@ -0,0 +1,109 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	procSetWindowsHookEx = user32.NewProc("SetWindowsHookExW")
+	procCallNextHookEx   = user32.NewProc("CallNextHookEx")
+	procUnhookWindowsHookEx = user32.NewProc("UnhookWindowsHookEx")
+	procGetMessage       = user32.NewProc("GetMessageW")
+)
+
+const whMouseLL = 14
+
+// msllhookstruct mirrors the Win32 MSLLHOOKSTRUCT passed to a WH_MOUSE_LL
+// hook procedure.
+type msllhookstruct struct {
+	ptX, ptY    int32
+	mouseData   uint32
+	flags       uint32
+	time        uint32
+	dwExtraInfo uintptr
+}
+
+// win32Capture is the Windows InputCapture backend, built on a low-level
+// mouse hook (SetWindowsHookExW WH_MOUSE_LL).
+type win32Capture struct {
+	baseCapture
+	hook   uintptr
+	done   chan struct{}
+}
+
+var activeWin32Capture *win32Capture
+
+func newPlatformInputCapture(opts Options) (InputCapture, error) {
+	return &win32Capture{
+		baseCapture: newBaseCapture(opts.BlockEvents),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+func (c *win32Capture) Start() error {
+	activeWin32Capture = c
+	hook, _, err := procSetWindowsHookEx.Call(
+		whMouseLL,
+		syscall.NewCallback(mouseHookProc),
+		0,
+		0,
+	)
+	if hook == 0 {
+		return fmt.Errorf("SetWindowsHookExW failed: %v", err)
+	}
+	c.hook = hook
+
+	go func() {
+		var msg [48]byte // MSG struct, opaque to us
+		for {
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			procGetMessage.Call(uintptr(unsafe.Pointer(&msg[0])), 0, 0, 0)
+		}
+	}()
+	return nil
+}
+
+func (c *win32Capture) Stop() {
+	close(c.done)
+	if c.hook != 0 {
+		procUnhookWindowsHookEx.Call(c.hook)
+	}
+	if activeWin32Capture == c {
+		activeWin32Capture = nil
+	}
+}
+
+// mouseHookProc is the WH_MOUSE_LL callback; wParam carries the Windows
+// message (WM_MOUSEMOVE, WM_LBUTTONDOWN, ...) and lParam points at an
+// MSLLHOOKSTRUCT.
+func mouseHookProc(nCode int, wParam, lParam uintptr) uintptr {
+	if nCode >= 0 && activeWin32Capture != nil {
+		info := (*msllhookstruct)(unsafe.Pointer(lParam))
+		ev := TrackpadEvent{X: float64(info.ptX), Y: float64(info.ptY)}
+		switch wParam {
+		case 0x0200: // WM_MOUSEMOVE
+			ev.Type = EventMouseMoved
+		case 0x0201: // WM_LBUTTONDOWN
+			ev.Type = EventLeftMouseDown
+		case 0x0202: // WM_LBUTTONUP
+			ev.Type = EventLeftMouseUp
+		default:
+			ev.Type = EventOther
+		}
+		activeWin32Capture.emit(ev)
+		if activeWin32Capture.isBlocked() {
+			return 1
+		}
+	}
+	ret, _, _ := procCallNextHookEx.Call(0, uintptr(nCode), wParam, lParam)
+	return ret
+}
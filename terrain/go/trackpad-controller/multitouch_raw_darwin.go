@@ -0,0 +1,342 @@
+//go:build darwin
+
+package main
+
+/*
+#cgo LDFLAGS: -F/System/Library/PrivateFrameworks -framework MultitouchSupport -framework CoreFoundation
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <stdint.h>
+
+typedef struct {
+    float x;
+    float y;
+} MTPoint;
+
+typedef struct {
+    MTPoint position;
+    MTPoint velocity;
+} MTVector;
+
+typedef struct {
+    int32_t frame;
+    double timestamp;
+    int32_t identifier;
+    int32_t state;
+    int32_t unknown1;
+    int32_t unknown2;
+    MTVector normalized;
+    float size;
+    int32_t unknown3;
+    float angle;
+    float majorAxis;
+    float minorAxis;
+    MTVector absolute;
+    int32_t unknown4;
+    int32_t unknown5;
+    float density;
+} MTTouch;
+
+typedef void* MTDeviceRef;
+// The callback's "device" argument is the originating MTDeviceRef, not an
+// index, so a process with several trackpads open can tell them apart.
+typedef int (*MTContactCallbackFunction)(intptr_t device, MTTouch* touches, int numTouches, double timestamp, int frame);
+
+extern CFArrayRef MTDeviceCreateList();
+extern MTDeviceRef MTDeviceCreateDefault();
+extern void MTRegisterContactFrameCallback(MTDeviceRef device, MTContactCallbackFunction callback);
+extern void MTDeviceStart(MTDeviceRef device, int unknown);
+extern void MTDeviceStop(MTDeviceRef device);
+extern void MTDeviceRelease(MTDeviceRef device);
+extern int MTDeviceGetDeviceID(MTDeviceRef device, uint64_t* outID);
+
+extern int goMultitouchCallback(intptr_t device, MTTouch* touches, int numTouches, double timestamp, int frame);
+
+static int multitouchCallbackBridge(intptr_t device, MTTouch* touches, int numTouches, double timestamp, int frame) {
+    return goMultitouchCallback(device, touches, numTouches, timestamp, frame);
+}
+
+static void registerMultitouchDevice(MTDeviceRef device) {
+    MTRegisterContactFrameCallback(device, multitouchCallbackBridge);
+    MTDeviceStart(device, 0);
+}
+*/
+import "C"
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// deviceEntry is one registered multitouch device: its CoreFoundation
+// reference, a stable identifier derived from MTDeviceGetDeviceID (falling
+// back to an enumeration index when the private call fails), and the
+// channel goMultitouchCallback delivers its frames on.
+type deviceEntry struct {
+	ref     C.MTDeviceRef
+	id      string
+	channel chan TouchFrame
+}
+
+// devices is keyed by the C device pointer, matching the "device" argument
+// each registered callback receives, so goMultitouchCallback can route a
+// frame to the MultitouchCapture instance that opened it.
+var (
+	devicesMu sync.Mutex
+	devices   = map[uintptr]*deviceEntry{}
+)
+
+//export goMultitouchCallback
+func goMultitouchCallback(device C.intptr_t, touches *C.MTTouch, numTouches C.int, timestamp C.double, frame C.int) C.int {
+	if numTouches == 0 {
+		return 0
+	}
+
+	devicesMu.Lock()
+	entry, ok := devices[uintptr(device)]
+	devicesMu.Unlock()
+	if !ok {
+		return 0
+	}
+
+	touchSlice := (*[100]C.MTTouch)(unsafe.Pointer(touches))[:numTouches:numTouches]
+
+	touchFrame := TouchFrame{
+		Timestamp: float64(timestamp),
+		Frame:     int(frame),
+		Touches:   make([]Touch, 0, numTouches),
+	}
+
+	for i := 0; i < int(numTouches); i++ {
+		mt := touchSlice[i]
+
+		angleDeg := float64(mt.angle) * 180.0 / math.Pi
+
+		touch := Touch{
+			Frame:      int(mt.frame),
+			Timestamp:  float64(mt.timestamp),
+			Identifier: int(mt.identifier),
+			State:      TouchState(mt.state),
+			X:          float64(mt.normalized.position.x),
+			Y:          float64(mt.normalized.position.y),
+			VelX:       float64(mt.normalized.velocity.x),
+			VelY:       float64(mt.normalized.velocity.y),
+			Size:       float64(mt.size),
+			Density:    float64(mt.density),
+			Angle:      angleDeg,
+			MajorAxis:  float64(mt.majorAxis),
+			MinorAxis:  float64(mt.minorAxis),
+			AbsX:       float64(mt.absolute.position.x),
+			AbsY:       float64(mt.absolute.position.y),
+		}
+
+		touchFrame.Touches = append(touchFrame.Touches, touch)
+	}
+
+	select {
+	case entry.channel <- touchFrame:
+	default:
+	}
+
+	return 0
+}
+
+// enumerateDevices returns every MTDeviceRef MTDeviceCreateList knows
+// about (built-in trackpad plus any external Magic Trackpads), falling
+// back to MTDeviceCreateDefault alone when the list comes back empty.
+func enumerateDevices() []C.MTDeviceRef {
+	list := C.MTDeviceCreateList()
+	if list != 0 {
+		defer C.CFRelease(C.CFTypeRef(list))
+		n := int(C.CFArrayGetCount(list))
+		if n > 0 {
+			out := make([]C.MTDeviceRef, 0, n)
+			for i := 0; i < n; i++ {
+				out = append(out, C.MTDeviceRef(C.CFArrayGetValueAtIndex(list, C.CFIndex(i))))
+			}
+			return out
+		}
+	}
+	if d := C.MTDeviceCreateDefault(); uintptr(unsafe.Pointer(d)) != 0 {
+		return []C.MTDeviceRef{d}
+	}
+	return nil
+}
+
+// deviceID names a device for display/logging: its MTDeviceGetDeviceID
+// serial when the private call succeeds, otherwise "trackpad-<index>".
+func deviceID(ref C.MTDeviceRef, index int) string {
+	var raw C.uint64_t
+	if C.MTDeviceGetDeviceID(ref, &raw) == 0 && raw != 0 {
+		return fmt.Sprintf("serial-%d", uint64(raw))
+	}
+	return fmt.Sprintf("trackpad-%d", index)
+}
+
+// MultitouchCapture opens every connected multitouch device and gives
+// each its own registered callback and TouchFrame channel, so a laptop
+// trackpad and an external Magic Trackpad can be consumed independently.
+type MultitouchCapture struct {
+	devices []*deviceEntry
+}
+
+func NewMultitouchCapture() (*MultitouchCapture, error) {
+	refs := enumerateDevices()
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("failed to initialize multitouch device - is this a Mac with a trackpad?")
+	}
+
+	mc := &MultitouchCapture{devices: make([]*deviceEntry, 0, len(refs))}
+
+	devicesMu.Lock()
+	for i, ref := range refs {
+		entry := &deviceEntry{
+			ref:     ref,
+			id:      deviceID(ref, i),
+			channel: make(chan TouchFrame, 100),
+		}
+		devices[uintptr(unsafe.Pointer(ref))] = entry
+		mc.devices = append(mc.devices, entry)
+	}
+	devicesMu.Unlock()
+
+	for _, entry := range mc.devices {
+		C.registerMultitouchDevice(entry.ref)
+	}
+
+	return mc, nil
+}
+
+// Devices returns the identifier of every device this capture opened, in
+// the same order as GetTouchChannel.
+func (mc *MultitouchCapture) Devices() []string {
+	ids := make([]string, len(mc.devices))
+	for i, entry := range mc.devices {
+		ids[i] = entry.id
+	}
+	return ids
+}
+
+func (mc *MultitouchCapture) Stop() {
+	devicesMu.Lock()
+	defer devicesMu.Unlock()
+	for _, entry := range mc.devices {
+		if uintptr(unsafe.Pointer(entry.ref)) == 0 {
+			continue
+		}
+		C.MTDeviceStop(entry.ref)
+		C.MTDeviceRelease(entry.ref)
+		delete(devices, uintptr(unsafe.Pointer(entry.ref)))
+		entry.ref = nil
+	}
+}
+
+// GetTouchChannel returns the default (first-enumerated) device's frame
+// channel, for callers that only care about one trackpad.
+func (mc *MultitouchCapture) GetTouchChannel() <-chan TouchFrame {
+	if len(mc.devices) == 0 {
+		return nil
+	}
+	return mc.devices[0].channel
+}
+
+// GetTouchChannelFor returns the frame channel for a specific device, as
+// named by Devices(), so a caller with a laptop trackpad and a Magic
+// Trackpad 2 can read both streams independently.
+func (mc *MultitouchCapture) GetTouchChannelFor(id string) <-chan TouchFrame {
+	for _, entry := range mc.devices {
+		if entry.id == id {
+			return entry.channel
+		}
+	}
+	return nil
+}
+
+// runRaw implements `capture raw [-record file] [-replay file] [-speed N]`:
+// streams raw multitouch frames from every connected device, or replays a
+// file written by -record, printing each frame to stdout.
+func runRaw(args []string) error {
+	fs := flag.NewFlagSet("raw", flag.ContinueOnError)
+	recordPath := fs.String("record", "", "record every captured frame to a file for later --replay")
+	replayPath := fs.String("replay", "", "replay a file written by --record instead of reading a real trackpad")
+	speed := fs.Float64("speed", 1.0, "replay speed multiplier, only used with --replay")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Println("macOS Raw Multitouch Data Capture")
+	fmt.Println("==================================")
+	fmt.Println()
+	fmt.Println("This captures raw multitouch data from every connected trackpad.")
+	fmt.Println("Note: This does NOT block events from reaching the OS.")
+	fmt.Println("Combine with the joystick subcommand for full control.")
+	fmt.Println()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	if *replayPath != "" {
+		player := NewPlayer(*replayPath, *speed)
+		if err := player.Start(); err != nil {
+			return err
+		}
+		defer player.Stop()
+
+		fmt.Printf("✓ Replaying %s\n", *replayPath)
+		processor := NewTouchProcessor()
+		clicks := NewClickDetector(DefaultForceTouchConfig())
+		for touchFrame := range player.Channel() {
+			touchFrame, events := clicks.Feed(touchFrame)
+			for _, ev := range events {
+				fmt.Printf("[replay] %+v\n", ev)
+			}
+			printTouchFrame("replay", processor, touchFrame)
+		}
+		return nil
+	}
+
+	capture, err := NewMultitouchCapture()
+	if err != nil {
+		return err
+	}
+	defer capture.Stop()
+
+	fmt.Printf("✓ Multitouch capture initialized: %v\n", capture.Devices())
+	fmt.Println("✓ Touch your trackpad to see raw data")
+	fmt.Println("✓ Press Ctrl+C to exit")
+	fmt.Println()
+
+	if *recordPath != "" {
+		sink, err := NewRecordingSink(*recordPath, capture.GetTouchChannel())
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
+		fmt.Printf("✓ Recording the default device to %s\n", *recordPath)
+	}
+
+	for _, id := range capture.Devices() {
+		id := id
+		ch := capture.GetTouchChannelFor(id)
+		go func() {
+			processor := NewTouchProcessor()
+			clicks := NewClickDetector(DefaultForceTouchConfig())
+			for touchFrame := range ch {
+				touchFrame, events := clicks.Feed(touchFrame)
+				for _, ev := range events {
+					fmt.Printf("[%s] %+v\n", id, ev)
+				}
+				printTouchFrame(id, processor, touchFrame)
+			}
+		}()
+	}
+
+	<-sigChan
+	fmt.Println("\n\nShutting down...")
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !darwin
+
+package main
+
+import "fmt"
+
+func runRaw(args []string) error {
+	return fmt.Errorf("raw multitouch capture is only supported on darwin")
+}
+
+func runJoystick(args []string) error {
+	return fmt.Errorf("joystick capture is only supported on darwin")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// touchFrameRecord is one newline-delimited JSON line written by a
+// RecordingSink and read back by a Player, pairing a TouchFrame with the
+// time it arrived relative to the start of the recording.
+type touchFrameRecord struct {
+	TsNS  int64      `json:"ts_ns"`
+	Frame TouchFrame `json:"frame"`
+}
+
+// RecordingSink subscribes to a TouchFrame channel (as returned by
+// MultitouchCapture.GetTouchChannel) and writes every frame to disk as
+// newline-delimited JSON, so a real trackpad session can be captured
+// once and replayed against TouchProcessor/gesture-recognizer tests on
+// CI machines with no trackpad hardware.
+type RecordingSink struct {
+	f       *os.File
+	w       *bufio.Writer
+	ch      <-chan TouchFrame
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewRecordingSink subscribes to ch and writes a newline-JSON record for
+// every frame received until Close is called.
+func NewRecordingSink(path string, ch <-chan TouchFrame) (io.Closer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &RecordingSink{f: f, w: bufio.NewWriter(f), ch: ch, done: make(chan struct{}), stopped: make(chan struct{})}
+
+	start := time.Now()
+	go func() {
+		defer close(s.stopped)
+		for {
+			select {
+			case <-s.done:
+				s.drain(start)
+				return
+			case frame, ok := <-ch:
+				if !ok {
+					return
+				}
+				s.writeRecord(start, frame)
+			}
+		}
+	}()
+	return s, nil
+}
+
+// writeRecord appends one newline-JSON record for frame, timestamped
+// relative to start.
+func (s *RecordingSink) writeRecord(start time.Time, frame TouchFrame) {
+	rec := touchFrameRecord{TsNS: time.Since(start).Nanoseconds(), Frame: frame}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	s.w.Write(data)
+	s.w.WriteByte('\n')
+}
+
+// drain flushes any frames already buffered on ch after Close has signaled
+// done, so a burst right before shutdown isn't lost to the select's random
+// case choice between done and a ready frame.
+func (s *RecordingSink) drain(start time.Time) {
+	for {
+		select {
+		case frame, ok := <-s.ch:
+			if !ok {
+				return
+			}
+			s.writeRecord(start, frame)
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the sink: it signals the background goroutine to stop
+// accepting new work, waits for it to drain whatever was already buffered
+// on ch, then flushes and closes the file.
+func (s *RecordingSink) Close() error {
+	close(s.done)
+	<-s.stopped
+	s.w.Flush()
+	return s.f.Close()
+}
+
+// Player replays a file written by RecordingSink into a channel
+// identical in shape to MultitouchCapture.GetTouchChannel(), reproducing
+// the original inter-frame timing scaled by Speed.
+type Player struct {
+	path  string
+	Speed float64
+	ch    chan TouchFrame
+	done  chan struct{}
+}
+
+// NewPlayer builds a Player over path; Start begins streaming it. Speed
+// scales playback (1.0 = real time, 2.0 = twice as fast).
+func NewPlayer(path string, speed float64) *Player {
+	if speed <= 0 {
+		speed = 1.0
+	}
+	return &Player{
+		path:  path,
+		Speed: speed,
+		ch:    make(chan TouchFrame, 100),
+		done:  make(chan struct{}),
+	}
+}
+
+// Start begins streaming the recorded frames in the background; the
+// channel returned by Channel() is closed once the file is exhausted.
+func (p *Player) Start() error {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	go p.run(f)
+	return nil
+}
+
+// Stop halts playback early; Channel() is closed once run notices.
+func (p *Player) Stop() {
+	close(p.done)
+}
+
+func (p *Player) Channel() <-chan TouchFrame {
+	return p.ch
+}
+
+func (p *Player) run(f *os.File) {
+	defer f.Close()
+	defer close(p.ch)
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var lastTs int64
+	for sc.Scan() {
+		var rec touchFrameRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+		if delta := rec.TsNS - lastTs; delta > 0 {
+			select {
+			case <-time.After(time.Duration(float64(delta) / p.Speed)):
+			case <-p.done:
+				return
+			}
+		}
+		lastTs = rec.TsNS
+
+		select {
+		case p.ch <- rec.Frame:
+		case <-p.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRecordingSinkAndPlayerRoundTrip(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "frames-*.jsonl")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	path := f.Name()
+	f.Close()
+
+	src := make(chan TouchFrame, 2)
+	sink, err := NewRecordingSink(path, src)
+	if err != nil {
+		t.Fatalf("NewRecordingSink: %v", err)
+	}
+	src <- TouchFrame{Frame: 1, Touches: []Touch{{Identifier: 0, X: 0.5, Y: 0.5}}}
+	src <- TouchFrame{Frame: 2, Touches: []Touch{{Identifier: 0, X: 0.6, Y: 0.5}}}
+	sink.Close()
+
+	player := NewPlayer(path, 100.0)
+	if err := player.Start(); err != nil {
+		t.Fatalf("player.Start: %v", err)
+	}
+	defer player.Stop()
+
+	var got []TouchFrame
+	for frame := range player.Channel() {
+		got = append(got, frame)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 replayed frames, got %d", len(got))
+	}
+	if got[0].Frame != 1 || got[1].Frame != 2 {
+		t.Fatalf("frames replayed out of order: %+v", got)
+	}
+	if got[1].Touches[0].X != 0.6 {
+		t.Fatalf("unexpected touch X on second frame: %+v", got[1])
+	}
+}
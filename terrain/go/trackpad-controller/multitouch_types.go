@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// TouchState and TouchFrame are platform-independent so ClickDetector,
+// RecordingSink/Player, and their tests can build and run on any GOOS; only
+// the cgo bridge that actually talks to MultitouchSupport (multitouch_raw_darwin.go)
+// is darwin-only.
+
+type TouchState int
+
+// These are every state value actually observed in an MTTouch.state
+// field across a session: TouchNotTracking (0) never appears in a
+// delivered frame (a device simply stops reporting the identifier), so
+// 1..7 is the lifecycle a real capture walks through.
+const (
+	TouchNotTracking TouchState = iota
+	TouchStartInRange
+	TouchHoverInRange
+	TouchMakeTouch
+	TouchTouching
+	TouchBreakTouch
+	TouchLingerInRange
+	TouchOutOfRange
+)
+
+func (ts TouchState) String() string {
+	states := []string{
+		"NotTracking", "StartInRange", "HoverInRange",
+		"MakeTouch", "Touching", "BreakTouch",
+		"LingerInRange", "OutOfRange",
+	}
+	if int(ts) < len(states) {
+		return states[ts]
+	}
+	return "Unknown"
+}
+
+type Touch struct {
+	Frame      int
+	Timestamp  float64
+	Identifier int
+	State      TouchState
+
+	X float64
+	Y float64
+
+	VelX float64
+	VelY float64
+
+	Size    float64
+	Density float64
+
+	// Click and Pressure are derived from State/Density by
+	// ClickDetector.Feed, not populated directly from MTTouch — a bare
+	// Touch built elsewhere (e.g. in a test) leaves them zero.
+	Click    bool
+	Pressure float64
+
+	Angle     float64
+	MajorAxis float64
+	MinorAxis float64
+
+	AbsX float64
+	AbsY float64
+}
+
+type TouchFrame struct {
+	Timestamp float64
+	Frame     int
+	Touches   []Touch
+}
+
+type TouchProcessor struct {
+	lastTouches map[int]Touch
+}
+
+func NewTouchProcessor() *TouchProcessor {
+	return &TouchProcessor{
+		lastTouches: make(map[int]Touch),
+	}
+}
+
+func (tp *TouchProcessor) ProcessFrame(frame TouchFrame) {
+	currentTouches := make(map[int]Touch)
+
+	for _, touch := range frame.Touches {
+		currentTouches[touch.Identifier] = touch
+
+		if _, exists := tp.lastTouches[touch.Identifier]; !exists {
+			if touch.State == TouchTouching {
+				fmt.Printf("🔵 New touch: ID=%d at (%.3f, %.3f)\n",
+					touch.Identifier, touch.X, touch.Y)
+			}
+		}
+	}
+
+	numTouches := len(frame.Touches)
+
+	if numTouches == 2 {
+		touches := frame.Touches
+		if len(touches) == 2 {
+			dx := touches[0].X - touches[1].X
+			dy := touches[0].Y - touches[1].Y
+			distance := math.Sqrt(dx*dx + dy*dy)
+
+			_ = distance
+		}
+	}
+
+	tp.lastTouches = currentTouches
+}
+
+// printTouchFrame runs touchFrame through processor and, if it carries
+// any touches, logs it the same way whether it came from a live device
+// or a --replay Player.
+func printTouchFrame(deviceID string, processor *TouchProcessor, touchFrame TouchFrame) {
+	processor.ProcessFrame(touchFrame)
+
+	if len(touchFrame.Touches) == 0 {
+		return
+	}
+
+	fmt.Printf("\n[%s/Frame %d] %.3fs - %d touch(es):\n",
+		deviceID, touchFrame.Frame, touchFrame.Timestamp, len(touchFrame.Touches))
+
+	for i, touch := range touchFrame.Touches {
+		fmt.Printf("  Touch %d [ID:%d]:\n", i+1, touch.Identifier)
+		fmt.Printf("    State:    %s\n", touch.State)
+		fmt.Printf("    Position: (%.3f, %.3f)\n", touch.X, touch.Y)
+		fmt.Printf("    Velocity: (%.3f, %.3f)\n", touch.VelX, touch.VelY)
+		fmt.Printf("    Size:     %.3f\n", touch.Size)
+		fmt.Printf("    Pressure: %.3f\n", touch.Density)
+		fmt.Printf("    Angle:    %.1f°\n", touch.Angle)
+		fmt.Printf("    Ellipse:  %.3f x %.3f\n", touch.MajorAxis, touch.MinorAxis)
+		fmt.Printf("    Absolute: (%.2fmm, %.2fmm)\n", touch.AbsX, touch.AbsY)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// EventSource is the thin subset of InputCapture a replayer needs to
+// satisfy: anything that can be started, stopped, and drained through an
+// Events() channel. Every backend already implements it, and future ones
+// (record/replay included) get it for free.
+type EventSource interface {
+	Start() error
+	Stop()
+	Events() <-chan TrackpadEvent
+}
+
+// eventRecord is one newline-delimited JSON line written by a FileRecorder
+// and read back by a FileReplayer.
+type eventRecord struct {
+	TsNS    int64   `json:"ts_ns"`
+	Type    int     `json:"type"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Buttons int     `json:"buttons"`
+}
+
+// fileRecorder subscribes to an EventSource and writes every event to disk
+// as newline-delimited JSON, so a captured session can be replayed later
+// without the OS event tap.
+type fileRecorder struct {
+	f       *os.File
+	w       *bufio.Writer
+	done    chan struct{}
+	stopped chan struct{}
+	source  EventSource
+}
+
+// NewFileRecorder subscribes to source.Events() and writes a newline-JSON
+// record for every event received until Close is called.
+func NewFileRecorder(path string, source EventSource) (io.Closer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &fileRecorder{
+		f:       f,
+		w:       bufio.NewWriter(f),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		source:  source,
+	}
+
+	start := time.Now()
+	go func() {
+		defer close(r.stopped)
+		for {
+			select {
+			case <-r.done:
+				r.drain(start)
+				return
+			case ev, ok := <-source.Events():
+				if !ok {
+					return
+				}
+				r.writeRecord(start, ev)
+			}
+		}
+	}()
+	return r, nil
+}
+
+// writeRecord appends one newline-JSON record for ev, timestamped relative
+// to start.
+func (r *fileRecorder) writeRecord(start time.Time, ev TrackpadEvent) {
+	rec := eventRecord{
+		TsNS:    time.Since(start).Nanoseconds(),
+		Type:    int(ev.Type),
+		X:       ev.X,
+		Y:       ev.Y,
+		Buttons: ev.Buttons,
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	r.w.Write(data)
+	r.w.WriteByte('\n')
+}
+
+// drain flushes any events already buffered on source.Events() after Close
+// has signaled done, so a burst right before shutdown isn't lost to the
+// select's random case choice between done and a ready event.
+func (r *fileRecorder) drain(start time.Time) {
+	for {
+		select {
+		case ev, ok := <-r.source.Events():
+			if !ok {
+				return
+			}
+			r.writeRecord(start, ev)
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the recorder: it signals the background goroutine to stop
+// accepting new work, waits for it to drain whatever was already buffered
+// on the event channel, then flushes and closes the file.
+func (r *fileRecorder) Close() error {
+	close(r.done)
+	<-r.stopped
+	r.w.Flush()
+	return r.f.Close()
+}
+
+// fileReplayer implements EventSource by reading back a FileRecorder log
+// and emitting the same TrackpadEvent values with the original inter-event
+// timing, scaled by Speed and optionally looped.
+type fileReplayer struct {
+	baseCapture
+	path  string
+	Speed float64
+	Loop  bool
+	done  chan struct{}
+}
+
+// NewFileReplayer builds an EventSource-compatible source that replays a log
+// written by NewFileRecorder. Speed scales playback (1.0 = real time, 2.0 =
+// twice as fast); Loop restarts from the beginning once the log is drained.
+func NewFileReplayer(path string, speed float64, loop bool) *fileReplayer {
+	if speed <= 0 {
+		speed = 1.0
+	}
+	return &fileReplayer{
+		baseCapture: newBaseCapture(false),
+		path:        path,
+		Speed:       speed,
+		Loop:        loop,
+		done:        make(chan struct{}),
+	}
+}
+
+func (r *fileReplayer) Start() error {
+	go r.run()
+	return nil
+}
+
+func (r *fileReplayer) Stop() {
+	close(r.done)
+}
+
+func (r *fileReplayer) run() {
+	for {
+		if !r.playOnce() || !r.Loop {
+			return
+		}
+	}
+}
+
+// playOnce streams one pass of the log file, sleeping between records to
+// reproduce the original timing. It returns false if Stop was called
+// mid-playback.
+func (r *fileReplayer) playOnce() bool {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	var lastTs int64
+	for sc.Scan() {
+		var rec eventRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+		if delta := rec.TsNS - lastTs; delta > 0 {
+			select {
+			case <-time.After(time.Duration(float64(delta) / r.Speed)):
+			case <-r.done:
+				return false
+			}
+		}
+		lastTs = rec.TsNS
+
+		r.emit(TrackpadEvent{
+			Type:    EventType(rec.Type),
+			X:       rec.X,
+			Y:       rec.Y,
+			Buttons: rec.Buttons,
+		})
+	}
+	return true
+}
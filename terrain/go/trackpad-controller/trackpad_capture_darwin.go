@@ -1,3 +1,5 @@
+//go:build darwin
+
 package main
 
 /*
@@ -12,9 +14,9 @@ extern void goEventCallback(int eventType, double x, double y, int buttons);
 CGEventRef eventTapCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon) {
     CGPoint location = CGEventGetLocation(event);
     int64_t buttons = CGEventGetIntegerValueField(event, kCGMouseEventButtonNumber);
-    
+
     goEventCallback((int)type, location.x, location.y, (int)buttons);
-    
+
     if (*(int*)refcon) {
         return NULL;
     }
@@ -31,7 +33,7 @@ CFMachPortRef createEventTap(int *blockEvents) {
                            CGEventMaskBit(kCGEventRightMouseDragged) |
                            CGEventMaskBit(kCGEventScrollWheel) |
                            kCGEventMaskForAllEvents;
-    
+
     CFMachPortRef eventTap = CGEventTapCreate(
         kCGHIDEventTap,
         kCGHeadInsertEventTap,
@@ -40,7 +42,7 @@ CFMachPortRef createEventTap(int *blockEvents) {
         eventTapCallback,
         blockEvents
     );
-    
+
     return eventTap;
 }
 
@@ -56,56 +58,26 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"unsafe"
 )
 
-type TrackpadEvent struct {
-	Type    EventType
-	X       float64
-	Y       float64
-	Buttons int
-}
-
-type EventType int
-
-const (
-	EventMouseMoved EventType = iota
-	EventLeftMouseDown
-	EventLeftMouseUp
-	EventRightMouseDown
-	EventRightMouseUp
-	EventLeftMouseDragged
-	EventRightMouseDragged
-	EventScrollWheel
-	EventGesture
-	EventOther
-)
-
-var (
-	eventChannel chan TrackpadEvent
-	eventMutex   sync.Mutex
-	blockEvents  bool = true
-)
-
-func init() {
-	eventChannel = make(chan TrackpadEvent, 100)
-}
+// eventChannel is package-level because the cgo callback has no way to
+// carry a Go receiver pointer through CGEventTapCreate's refcon; it is
+// fanned into whichever *TrackpadCapture is currently running via emit.
+var activeCapture *TrackpadCapture
 
 //export goEventCallback
 func goEventCallback(eventType C.int, x C.double, y C.double, buttons C.int) {
-	event := TrackpadEvent{
+	if activeCapture == nil {
+		return
+	}
+	activeCapture.emit(TrackpadEvent{
 		Type:    mapEventType(int(eventType)),
 		X:       float64(x),
 		Y:       float64(y),
 		Buttons: int(buttons),
-	}
-	
-	select {
-	case eventChannel <- event:
-	default:
-	}
+	})
 }
 
 func mapEventType(cType int) EventType {
@@ -133,75 +105,74 @@ func mapEventType(cType int) EventType {
 	}
 }
 
-func (e EventType) String() string {
-	names := []string{
-		"MouseMoved", "LeftMouseDown", "LeftMouseUp",
-		"RightMouseDown", "RightMouseUp",
-		"LeftMouseDragged", "RightMouseDragged",
-		"ScrollWheel", "Gesture", "Other",
-	}
-	if int(e) < len(names) {
-		return names[e]
-	}
-	return "Unknown"
+// TrackpadCapture is the macOS InputCapture backend, built on a
+// CGEventTap.
+type TrackpadCapture struct {
+	baseCapture
+	eventTap   C.CFMachPortRef
+	runLoopSrc C.CFRunLoopSourceRef
+	blockFlag  *C.int
+	gestures   *GestureRecognizer
 }
 
-type TrackpadCapture struct {
-	eventTap     C.CFMachPortRef
-	runLoopSrc   C.CFRunLoopSourceRef
-	blockFlag    *C.int
-	stopChannel  chan struct{}
+func newPlatformInputCapture(opts Options) (InputCapture, error) {
+	return NewTrackpadCapture(opts.BlockEvents)
 }
 
 func NewTrackpadCapture(blockEvents bool) (*TrackpadCapture, error) {
 	if C.checkAccessibility() == 0 {
 		return nil, fmt.Errorf("accessibility permissions not granted - please enable in System Preferences > Security & Privacy > Privacy > Accessibility")
 	}
-	
+
 	tc := &TrackpadCapture{
+		baseCapture: newBaseCapture(blockEvents),
 		blockFlag:   (*C.int)(C.malloc(C.sizeof_int)),
-		stopChannel: make(chan struct{}),
 	}
-	
+
 	if blockEvents {
 		*tc.blockFlag = 1
 	} else {
 		*tc.blockFlag = 0
 	}
-	
+
 	tc.eventTap = C.createEventTap(tc.blockFlag)
 	if uintptr(unsafe.Pointer(tc.eventTap)) == 0 {
 		C.free(unsafe.Pointer(tc.blockFlag))
 		return nil, fmt.Errorf("failed to create event tap - ensure accessibility permissions are granted and app is trusted")
 	}
-	
+
 	tc.runLoopSrc = C.CFMachPortCreateRunLoopSource(
 		C.kCFAllocatorDefault,
 		tc.eventTap,
 		0,
 	)
-	
+
 	return tc, nil
 }
 
-func (tc *TrackpadCapture) Start() {
+func (tc *TrackpadCapture) Start() error {
+	activeCapture = tc
+
 	C.CFRunLoopAddSource(
 		C.CFRunLoopGetCurrent(),
 		tc.runLoopSrc,
 		C.kCFRunLoopCommonModes,
 	)
-	
+
 	C.CGEventTapEnable(tc.eventTap, C.bool(true))
-	
+	tc.startGestureMonitors()
+
 	go func() {
 		C.CFRunLoopRun()
 	}()
+	return nil
 }
 
 func (tc *TrackpadCapture) Stop() {
+	tc.stopGestureMonitors()
 	C.CGEventTapEnable(tc.eventTap, C.bool(false))
 	C.CFRunLoopStop(C.CFRunLoopGetCurrent())
-	
+
 	if uintptr(unsafe.Pointer(tc.runLoopSrc)) != 0 {
 		C.CFRelease(C.CFTypeRef(tc.runLoopSrc))
 	}
@@ -211,12 +182,15 @@ func (tc *TrackpadCapture) Stop() {
 	if tc.blockFlag != nil {
 		C.free(unsafe.Pointer(tc.blockFlag))
 	}
+	if activeCapture == tc {
+		activeCapture = nil
+	}
 }
 
+// SetBlocking overrides baseCapture.SetBlocking to also flip the C-side
+// blockFlag the event tap callback reads.
 func (tc *TrackpadCapture) SetBlocking(block bool) {
-	eventMutex.Lock()
-	defer eventMutex.Unlock()
-	
+	tc.baseCapture.SetBlocking(block)
 	if block {
 		*tc.blockFlag = 1
 	} else {
@@ -224,36 +198,39 @@ func (tc *TrackpadCapture) SetBlocking(block bool) {
 	}
 }
 
-func (tc *TrackpadCapture) GetEventChannel() <-chan TrackpadEvent {
-	return eventChannel
-}
-
 func main() {
+	if handled, err := runCaptureCLI(os.Args[1:]); handled {
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+
 	fmt.Println("macOS Trackpad Capture Example")
 	fmt.Println("===============================")
 	fmt.Println()
-	
-	capture, err := NewTrackpadCapture(true)
+
+	capture, err := NewInputCapture(Options{BlockEvents: true})
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 	defer capture.Stop()
-	
+
 	fmt.Println("✓ Trackpad capture initialized")
 	fmt.Println("✓ Events will be blocked from reaching OS")
 	fmt.Println("✓ Press Ctrl+C to exit")
 	fmt.Println()
-	
+
 	capture.Start()
-	
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	
+
 	go func() {
 		eventCount := 0
-		for event := range capture.GetEventChannel() {
+		for event := range capture.Events() {
 			eventCount++
-			
+
 			if eventCount%10 == 0 {
 				fmt.Printf("[%6d] %s at (%.1f, %.1f) buttons=%d\n",
 					eventCount,
@@ -262,14 +239,13 @@ func main() {
 					event.Y,
 					event.Buttons)
 			}
-			
+
 			if event.Type == EventRightMouseDown {
 				fmt.Println("\n→ Right click detected - toggling event blocking")
 			}
 		}
 	}()
-	
+
 	<-sigChan
 	fmt.Println("\n\nShutting down...")
 }
-
@@ -0,0 +1,125 @@
+package main
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices -framework CoreGraphics
+
+#include <ApplicationServices/ApplicationServices.h>
+
+static void postKeyEvent(CGKeyCode key, CGEventFlags flags, bool down) {
+    CGEventRef event = CGEventCreateKeyboardEvent(NULL, key, down);
+    if (event == NULL) {
+        return;
+    }
+    if (flags) {
+        CGEventSetFlags(event, flags);
+    }
+    CGEventPost(kCGHIDEventTap, event);
+    CFRelease(event);
+}
+
+static void postScrollEvent(int32_t dy, int32_t dx) {
+    CGEventRef event = CGEventCreateScrollWheelEvent(NULL, kCGScrollEventUnitLine, 2, dy, dx);
+    if (event == NULL) {
+        return;
+    }
+    CGEventPost(kCGHIDEventTap, event);
+    CFRelease(event);
+}
+*/
+import "C"
+import (
+	"encoding/json"
+	"os"
+)
+
+// GestureKind names the high-level gesture a GestureBinding matches —
+// the kinds goMultitouchCallback can tell apart from raw touch counts
+// and finger motion (see controller_combined.go).
+type GestureKind string
+
+const (
+	GesturePinch GestureKind = "pinch"
+	GestureSwipe GestureKind = "swipe"
+	GestureDrag  GestureKind = "drag"
+)
+
+// Action is what a matched gesture should be remapped to: a keypress
+// (optionally chorded with modifiers) or a scroll delta. It covers
+// "three-finger swipe -> Cmd+Tab", "pinch -> Ctrl+scroll for zoom", and
+// a custom two-finger drag region -> arrow keys for a game.
+type Action struct {
+	KeyCode   uint16   `json:"key_code,omitempty"`
+	Modifiers []string `json:"modifiers,omitempty"`
+	ScrollDX  int32    `json:"scroll_dx,omitempty"`
+	ScrollDY  int32    `json:"scroll_dy,omitempty"`
+}
+
+// GestureBinding maps one (gesture, finger count) pair to an Action.
+type GestureBinding struct {
+	Gesture GestureKind `json:"gesture"`
+	Fingers int         `json:"fingers"`
+	Action  Action      `json:"action"`
+}
+
+// LoadGestureBindings reads a JSON array of GestureBinding from path,
+// the config format VirtualInputController consults to decide which
+// synthetic events to inject in place of the suppressed native ones.
+func LoadGestureBindings(path string) ([]GestureBinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bindings []GestureBinding
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+var modifierFlags = map[string]C.CGEventFlags{
+	"cmd":   C.kCGEventFlagMaskCommand,
+	"ctrl":  C.kCGEventFlagMaskControl,
+	"alt":   C.kCGEventFlagMaskAlternate,
+	"shift": C.kCGEventFlagMaskShift,
+}
+
+// VirtualInputController translates recognized gestures into synthesized
+// CGEvents according to its GestureBinding table, so a gesture blocked
+// from reaching the OS (blockFlag set) can be remapped to something else
+// entirely rather than simply discarded.
+type VirtualInputController struct {
+	bindings []GestureBinding
+}
+
+// NewVirtualInputController wraps bindings for lookup during gesture
+// handling; pass the result of LoadGestureBindings.
+func NewVirtualInputController(bindings []GestureBinding) *VirtualInputController {
+	return &VirtualInputController{bindings: bindings}
+}
+
+// HandleGesture looks up a binding for (gesture, fingers) and, if found,
+// synthesizes its Action, returning whether it matched.
+func (v *VirtualInputController) HandleGesture(gesture GestureKind, fingers int) bool {
+	for _, b := range v.bindings {
+		if b.Gesture == gesture && b.Fingers == fingers {
+			inject(b.Action)
+			return true
+		}
+	}
+	return false
+}
+
+// inject synthesizes the OS event(s) action describes: a scroll delta if
+// set, otherwise a key-down/key-up pair chorded with Modifiers.
+func inject(action Action) {
+	if action.ScrollDX != 0 || action.ScrollDY != 0 {
+		C.postScrollEvent(C.int32_t(action.ScrollDY), C.int32_t(action.ScrollDX))
+		return
+	}
+	var flags C.CGEventFlags
+	for _, m := range action.Modifiers {
+		flags |= modifierFlags[m]
+	}
+	C.postKeyEvent(C.CGKeyCode(action.KeyCode), flags, true)
+	C.postKeyEvent(C.CGKeyCode(action.KeyCode), flags, false)
+}
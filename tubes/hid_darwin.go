@@ -0,0 +1,181 @@
+//go:build darwin
+
+package main
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <IOKit/IOKitLib.h>
+#include <IOKit/hid/IOHIDManager.h>
+#include <IOKit/hid/IOHIDDevice.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <stdint.h>
+
+CFStringRef createCFString(const char *str) {
+    return CFStringCreateWithCString(kCFAllocatorDefault, str, kCFStringEncodingUTF8);
+}
+
+void deviceMatchedCallback(void *context, IOReturn result, void *sender, IOHIDDeviceRef device) {
+    CFStringRef productName = IOHIDDeviceGetProperty(device, CFSTR(kIOHIDProductKey));
+    CFNumberRef usagePage = IOHIDDeviceGetProperty(device, CFSTR(kIOHIDPrimaryUsagePageKey));
+    CFNumberRef usage = IOHIDDeviceGetProperty(device, CFSTR(kIOHIDPrimaryUsageKey));
+
+    char name[256] = "Unknown";
+    int up = 0, u = 0;
+    if (productName) CFStringGetCString(productName, name, sizeof(name), kCFStringEncodingUTF8);
+    if (usagePage) CFNumberGetValue(usagePage, kCFNumberIntType, &up);
+    if (usage) CFNumberGetValue(usage, kCFNumberIntType, &u);
+    (void)name; (void)up; (void)u; // surfaced to Go via the input callback instead of printf
+}
+
+extern void hidInputCallback(uintptr_t handle, int usagePage, int usage, long value);
+
+void inputCallback(void *context, IOReturn result, void *sender, IOHIDValueRef value) {
+    IOHIDElementRef element = IOHIDValueGetElement(value);
+    uint32_t usagePage = IOHIDElementGetUsagePage(element);
+    uint32_t usage = IOHIDElementGetUsage(element);
+    CFIndex intValue = IOHIDValueGetIntegerValue(value);
+
+    hidInputCallback((uintptr_t)context, (int)usagePage, (int)usage, (long)intValue);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hidEventMsg carries one decoded HID input report into the tea.Program.
+type hidEventMsg struct {
+	page  int
+	usage int
+	value int
+}
+
+// hidVendorID restricts the scan to Apple's vendor (trackpads, in
+// particular, show up as a Digitizer usage page under this vendor).
+const hidVendorID = 0x05ac
+
+// hidManager owns the IOHIDManager run loop and the channel that
+// inputCallback's C side feeds into.
+type hidManager struct {
+	manager C.IOHIDManagerRef
+	events  chan hidEventMsg
+	stop    chan struct{}
+	filter  int // usage page filter; 0 means "all"
+	mu      sync.Mutex
+}
+
+//export hidInputCallback
+func hidInputCallback(handle C.uintptr_t, usagePage, usage C.int, value C.long) {
+	h := cgo.Handle(handle)
+	hm, ok := h.Value().(*hidManager)
+	if !ok {
+		return
+	}
+	hm.mu.Lock()
+	filter := hm.filter
+	hm.mu.Unlock()
+	if filter != 0 && int(usagePage) != filter {
+		return
+	}
+	select {
+	case hm.events <- hidEventMsg{page: int(usagePage), usage: int(usage), value: int(value)}:
+	default:
+	}
+}
+
+// StartHIDManager replaces the old standalone HID scanner's main(): it opens
+// an IOHIDManager matching Apple devices and forwards every input report as
+// an hidEventMsg to program, so the Tubes TUI can graph touch activity live.
+func StartHIDManager(program *tea.Program) error {
+	runtime.LockOSThread()
+
+	manager := C.IOHIDManagerCreate(C.kCFAllocatorDefault, C.kIOHIDOptionsTypeNone)
+	if manager == 0 {
+		return fmt.Errorf("failed to create HID manager")
+	}
+
+	hm := &hidManager{manager: manager, events: make(chan hidEventMsg, 256), stop: make(chan struct{})}
+	handle := cgo.NewHandle(hm)
+
+	vendorKey := C.createCFString(C.CString("VendorID"))
+	defer C.CFRelease(C.CFTypeRef(vendorKey))
+	vendorID := C.int(hidVendorID)
+	vendorNum := C.CFNumberCreate(C.kCFAllocatorDefault, C.kCFNumberIntType, unsafe.Pointer(&vendorID))
+	defer C.CFRelease(C.CFTypeRef(vendorNum))
+
+	keys := []unsafe.Pointer{unsafe.Pointer(vendorKey)}
+	values := []unsafe.Pointer{unsafe.Pointer(vendorNum)}
+	matchDict := C.CFDictionaryCreate(
+		C.kCFAllocatorDefault,
+		&keys[0], &values[0], 1,
+		&C.kCFTypeDictionaryKeyCallBacks,
+		&C.kCFTypeDictionaryValueCallBacks,
+	)
+	defer C.CFRelease(C.CFTypeRef(matchDict))
+
+	C.IOHIDManagerSetDeviceMatching(manager, C.CFDictionaryRef(matchDict))
+	C.IOHIDManagerRegisterDeviceMatchingCallback(manager, C.IOHIDDeviceCallback(C.deviceMatchedCallback), nil)
+	C.IOHIDManagerRegisterInputValueCallback(manager, C.IOHIDValueCallback(C.inputCallback), unsafe.Pointer(uintptr(handle)))
+
+	if result := C.IOHIDManagerOpen(manager, C.kIOHIDOptionsTypeNone); result != C.kIOReturnSuccess {
+		handle.Delete()
+		return fmt.Errorf("IOHIDManagerOpen failed: %d", result)
+	}
+	C.IOHIDManagerScheduleWithRunLoop(manager, C.CFRunLoopGetCurrent(), C.kCFRunLoopDefaultMode)
+
+	go func() {
+		defer handle.Delete()
+		defer C.IOHIDManagerClose(manager, C.kIOHIDOptionsTypeNone)
+		for {
+			select {
+			case <-hm.stop:
+				return
+			case ev := <-hm.events:
+				program.Send(ev)
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-hm.stop:
+				return
+			default:
+				C.CFRunLoopRunInMode(C.kCFRunLoopDefaultMode, 0.1, 1)
+			}
+		}
+	}()
+
+	globalHIDManager = hm
+	return nil
+}
+
+// globalHIDManager backs the /hid start|stop|filter commands; nil until
+// StartHIDManager has been called once.
+var globalHIDManager *hidManager
+
+// StopHIDManager halts the HID run loop started by StartHIDManager.
+func StopHIDManager() {
+	if globalHIDManager != nil {
+		close(globalHIDManager.stop)
+		globalHIDManager = nil
+	}
+}
+
+// SetHIDFilter restricts delivered events to a single HID usage page, or
+// clears the filter when page is 0.
+func SetHIDFilter(page int) {
+	if globalHIDManager == nil {
+		return
+	}
+	globalHIDManager.mu.Lock()
+	globalHIDManager.filter = page
+	globalHIDManager.mu.Unlock()
+}
@@ -0,0 +1,27 @@
+//go:build !darwin
+
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hidEventMsg mirrors the darwin definition so the rest of the TUI can
+// reference it regardless of platform.
+type hidEventMsg struct {
+	page  int
+	usage int
+	value int
+}
+
+// StartHIDManager is a no-op outside darwin; the HID scanner is built on
+// IOHIDManager, which only exists on macOS.
+func StartHIDManager(program *tea.Program) error {
+	return fmt.Errorf("--hid is only supported on darwin")
+}
+
+func StopHIDManager() {}
+
+func SetHIDFilter(page int) {}
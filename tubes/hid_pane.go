@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hidSparkHistory bounds how many recent touch events the sparkline pane
+// graphs.
+const hidSparkHistory = 120
+
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// renderHIDSparkline graphs the last N touch event values as a sparkline
+// using block characters.
+func renderHIDSparkline(values []int) string {
+	if len(values) == 0 {
+		return "(no HID events yet)"
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	span := hi - lo
+	if span == 0 {
+		span = 1
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := (v - lo) * (len(sparkBlocks) - 1) / span
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// handleHIDEvent records a touch event into the sparkline history and
+// refreshes the right pane when it is the active view.
+func (m *model) handleHIDEvent(ev hidEventMsg) {
+	m.hidHistory = append(m.hidHistory, ev.value)
+	if len(m.hidHistory) > hidSparkHistory {
+		m.hidHistory = m.hidHistory[len(m.hidHistory)-hidSparkHistory:]
+	}
+	if m.hidSparkOn {
+		m.renderHIDPane()
+	}
+}
+
+// renderHIDPane pushes the current sparkline into the right viewport.
+func (m *model) renderHIDPane() {
+	lines := []string{
+		"HID touch activity",
+		fmt.Sprintf("events seen: %d", len(m.hidHistory)),
+		"",
+		renderHIDSparkline(m.hidHistory),
+	}
+	m.rightContent = lines
+	m.rightVP.SetContent(strings.Join(lines, "\n"))
+}
+
+// hidCommand implements `/hid start|stop|filter <usagePage>`.
+func hidCommand(m *model, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("usage: /hid start|stop|filter <usagePage>")
+	}
+	switch args[0] {
+	case "start":
+		if err := StartHIDManager(m.program); err != nil {
+			return "", err
+		}
+		m.hidEnabled = true
+		m.hidSparkOn = true
+		m.renderHIDPane()
+		return "HID scanner started", nil
+	case "stop":
+		StopHIDManager()
+		m.hidEnabled = false
+		m.hidSparkOn = false
+		return "HID scanner stopped", nil
+	case "filter":
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: /hid filter <usagePage>")
+		}
+		page, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid usage page %q: %w", args[1], err)
+		}
+		SetHIDFilter(page)
+		return fmt.Sprintf("HID filter set to usage page %d", page), nil
+	default:
+		return "", fmt.Errorf("unknown /hid subcommand %q", args[0])
+	}
+}
+
+// hidUpdate lets the bubbletea Update loop react to hidEventMsg without
+// bloating the switch in main.go.
+func hidUpdate(m *model, msg tea.Msg) {
+	if ev, ok := msg.(hidEventMsg); ok {
+		m.handleHIDEvent(ev)
+	}
+}
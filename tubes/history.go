@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// historyPath returns ~/.tubes/history, creating ~/.tubes if needed.
+func historyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".tubes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+const historyRingSize = 500
+
+// loadHistory reads the ring-buffered REPL history, oldest first.
+func loadHistory() []string {
+	path, err := historyPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) > historyRingSize {
+		lines = lines[len(lines)-historyRingSize:]
+	}
+	return lines
+}
+
+// appendHistory persists an accepted REPL input, trimming the file back to
+// historyRingSize entries.
+func appendHistory(input string) {
+	path, err := historyPath()
+	if err != nil {
+		return
+	}
+	lines := loadHistory()
+	lines = append(lines, input)
+	if len(lines) > historyRingSize {
+		lines = lines[len(lines)-historyRingSize:]
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		w.WriteString(l)
+		w.WriteByte('\n')
+	}
+	w.Flush()
+}
+
+// paletteMode selects what a fuzzy search strip over the footer is matching.
+type paletteMode int
+
+const (
+	paletteOff paletteMode = iota
+	paletteHistory         // Ctrl+R: reverse-history search
+	paletteCommands        // Ctrl+P: command palette
+)
+
+// fuzzyMatch holds a ranked candidate plus the matched rune indexes so the
+// footer can highlight them with the completion style.
+type fuzzyMatch struct {
+	text    string
+	indexes []int
+}
+
+// fuzzySearch ranks source against query using sahilm/fuzzy, falling back to
+// the plain prefix behavior when strict mode is enabled.
+func fuzzySearch(query string, source []string, strict bool) []fuzzyMatch {
+	if strict {
+		var out []fuzzyMatch
+		for _, s := range source {
+			if len(query) == 0 || (len(s) >= len(query) && s[:len(query)] == query) {
+				out = append(out, fuzzyMatch{text: s})
+			}
+		}
+		return out
+	}
+
+	matches := fuzzy.Find(query, source)
+	out := make([]fuzzyMatch, 0, len(matches))
+	for _, mt := range matches {
+		out = append(out, fuzzyMatch{text: mt.Str, indexes: mt.MatchedIndexes})
+	}
+	return out
+}
+
+// commandPaletteSource returns every command name and description, so typing
+// "theme" finds "/theme" via its Description as well as its Name.
+func (m *model) commandPaletteSource() []string {
+	out := make([]string, 0, len(m.commands)*2)
+	for name, cmd := range m.commands {
+		out = append(out, name)
+		out = append(out, cmd.Description)
+	}
+	return out
+}
+
+// highlightMatch renders text with the matched rune positions styled via the
+// completion lipgloss style.
+func highlightMatch(text string, indexes []int, completionStyle func(string) string) string {
+	if len(indexes) == 0 {
+		return text
+	}
+	idxSet := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		idxSet[i] = true
+	}
+	var out []rune
+	for i, r := range text {
+		if idxSet[i] {
+			out = append(out, []rune(completionStyle(string(r)))...)
+		} else {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
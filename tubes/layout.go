@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Widget is a named, resizable region of the layout grid. Widgets wrap the
+// model's existing panes (logs, api, hid, md) so the gotop-style layout DSL
+// can place them anywhere instead of the old hardcoded left/right split.
+type Widget interface {
+	View(width, height int) string
+	Update(tea.Msg) tea.Cmd
+	Name() string
+}
+
+// layoutCell is one leaf of a parsed layout: a widget name plus the column
+// weight it was given (default 1).
+type layoutCell struct {
+	widget string
+	weight int
+}
+
+// layoutRow is one line of the spec: a row height weight plus its cells.
+type layoutRow struct {
+	weight int
+	cells  []layoutCell
+}
+
+// parseLayout parses a gotop-style layout spec: rows are separated by
+// newlines, widgets within a row by spaces. A row (or cell) may be prefixed
+// with "N:" to set its height (row) or width (cell) weight; a cell may also
+// carry a "/N" suffix for its column weight, e.g.:
+//
+//	2:logs
+//	api/1 2:hid/2
+//	md
+func parseLayout(spec string) ([]layoutRow, error) {
+	var rows []layoutRow
+	for _, line := range strings.Split(strings.TrimSpace(spec), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		row := layoutRow{weight: 1}
+		tokens := strings.Fields(line)
+		for _, tok := range tokens {
+			weight := 1
+			if idx := strings.Index(tok, ":"); idx >= 0 {
+				n, err := strconv.Atoi(tok[:idx])
+				if err != nil {
+					return nil, fmt.Errorf("invalid row weight in %q: %w", tok, err)
+				}
+				row.weight = n
+				tok = tok[idx+1:]
+			}
+			if idx := strings.Index(tok, "/"); idx >= 0 {
+				n, err := strconv.Atoi(tok[idx+1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid column weight in %q: %w", tok, err)
+				}
+				weight = n
+				tok = tok[:idx]
+			}
+			if tok == "" {
+				return nil, fmt.Errorf("empty widget name in row %q", line)
+			}
+			row.cells = append(row.cells, layoutCell{widget: tok, weight: weight})
+		}
+		if len(row.cells) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("layout spec has no rows")
+	}
+	return rows, nil
+}
+
+// layoutPresets ships three ready-made layouts: the original 1/3-2/3 split,
+// a minimal REPL+logs view, and a 4-pane kitchen sink with HID + markdown.
+var layoutPresets = map[string]string{
+	"default":     "logs/1 api/2",
+	"minimal":     "logs",
+	"kitchensink": "logs/1 api/1\nhid/1 md/1",
+}
+
+// resolveLayoutSpec expands a preset name, otherwise treats spec as a raw
+// layout string.
+func resolveLayoutSpec(spec string) string {
+	if preset, ok := layoutPresets[spec]; ok {
+		return preset
+	}
+	return spec
+}
+
+// renderLayout walks the parsed row/cell grid and renders each widget's View
+// at its computed rectangle, joining cells horizontally and rows vertically.
+// A cell whose widget is owned by the active pane (see m.widgetPane) is
+// bordered with paneBorderActive so Tab's focus change stays visible.
+func (m *model) renderLayout(width, height int) string {
+	rows := m.layoutRows
+	if len(rows) == 0 {
+		return ""
+	}
+
+	s := m.getStyles()
+	totalRowWeight := 0
+	for _, r := range rows {
+		totalRowWeight += r.weight
+	}
+
+	var renderedRows []string
+	for _, row := range rows {
+		rowH := height * row.weight / totalRowWeight
+		if rowH < 1 {
+			rowH = 1
+		}
+
+		totalColWeight := 0
+		for _, c := range row.cells {
+			totalColWeight += c.weight
+		}
+
+		var renderedCells []string
+		for _, cell := range row.cells {
+			colW := width * cell.weight / totalColWeight
+			if colW < 1 {
+				colW = 1
+			}
+			cellStyle := s.paneBorder
+			if owner, ok := m.widgetPane[cell.widget]; ok && owner == m.activePane {
+				cellStyle = s.paneBorderActive
+			}
+			innerW, innerH := colW-2, rowH-2
+			if innerW < 0 {
+				innerW = 0
+			}
+			if innerH < 0 {
+				innerH = 0
+			}
+
+			w, ok := m.widgets[cell.widget]
+			body := fmt.Sprintf("(unknown widget %q)", cell.widget)
+			if ok {
+				body = w.View(innerW, innerH)
+			}
+			renderedCells = append(renderedCells, cellStyle.Width(innerW).Height(innerH).Render(body))
+		}
+		renderedRows = append(renderedRows, lipgloss.JoinHorizontal(lipgloss.Top, renderedCells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, renderedRows...)
+}
+
+// applyLayout parses and installs a new layout spec, persisting it to
+// ~/.tubes/config so the choice survives a restart.
+func (m *model) applyLayout(spec string) error {
+	rows, err := parseLayout(resolveLayoutSpec(spec))
+	if err != nil {
+		return err
+	}
+	m.layoutRows = rows
+	m.layoutSpec = spec
+	saveLayoutConfig(spec)
+	return nil
+}
+
+// layoutCommand implements `/layout <preset-or-spec>`.
+func layoutCommand(m *model, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("usage: /layout <default|minimal|kitchensink|spec...>")
+	}
+	spec := strings.Join(args, " ")
+	if err := m.applyLayout(spec); err != nil {
+		return "", err
+	}
+	m.resizeLayout()
+	return fmt.Sprintf("Layout set to %q", spec), nil
+}
+
+func configPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".tubes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config"), nil
+}
+
+// saveLayoutConfig persists the active layout spec to ~/.tubes/config.
+func saveLayoutConfig(spec string) {
+	path, err := configPath()
+	if err != nil {
+		return
+	}
+	os.WriteFile(path, []byte("layout="+spec+"\n"), 0644)
+}
+
+// loadLayoutConfig reads a previously persisted layout spec, if any.
+func loadLayoutConfig() (string, bool) {
+	path, err := configPath()
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "layout="); ok {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// ExpandLSystem rewrites axiom by replacing every 'F' with rule, steps times
+// — the same single-symbol grammar used by the standalone L-system demo.
+func ExpandLSystem(axiom, rule string, steps int) string {
+	result := axiom
+	for i := 0; i < steps; i++ {
+		var next strings.Builder
+		for _, c := range result {
+			if c == 'F' {
+				next.WriteString(rule)
+			} else {
+				next.WriteRune(c)
+			}
+		}
+		result = next.String()
+	}
+	return result
+}
+
+// lsysGridSize bounds the ASCII turtle-graphics canvas rendered into the
+// right viewport by `/lsys`.
+const lsysGridSize = 40
+
+// renderLSystemASCII interprets an expanded L-system string as turtle moves
+// (F: forward+draw, +/-: turn, []: push/pop state) and rasterizes the result
+// onto a block-character grid.
+func renderLSystemASCII(instructions string, angle float64) string {
+	type turtleState struct {
+		x, y float64
+		dir  float64
+	}
+
+	grid := make([][]byte, lsysGridSize)
+	for i := range grid {
+		grid[i] = make([]byte, lsysGridSize)
+		for j := range grid[i] {
+			grid[i][j] = ' '
+		}
+	}
+
+	var stack []turtleState
+	x, y := float64(lsysGridSize)/2, float64(lsysGridSize)-2
+	dir := -90.0
+
+	set := func(x, y float64) {
+		gx, gy := int(math.Round(x)), int(math.Round(y))
+		if gx >= 0 && gx < lsysGridSize && gy >= 0 && gy < lsysGridSize {
+			grid[gy][gx] = '#'
+		}
+	}
+
+	for _, c := range instructions {
+		switch c {
+		case 'F':
+			rad := dir * math.Pi / 180
+			x, y = x+math.Cos(rad), y+math.Sin(rad)
+			set(x, y)
+		case '+':
+			dir += angle
+		case '-':
+			dir -= angle
+		case '[':
+			stack = append(stack, turtleState{x, y, dir})
+		case ']':
+			if n := len(stack); n > 0 {
+				s := stack[n-1]
+				stack = stack[:n-1]
+				x, y, dir = s.x, s.y, s.dir
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range grid {
+		b.Write(row)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -140,6 +140,32 @@ type model struct {
 	themeName     string
 	suggestions   []string
 	footerHelp    string
+
+	// sessionID identifies this model's *tea.Program in globalSessions; empty
+	// for the local (non-SSH) instance.
+	sessionID string
+
+	// REPL history & fuzzy palette
+	history         []string
+	palette         paletteMode
+	paletteMatches  []fuzzyMatch
+	paletteSelected int
+	strict          bool // Ctrl+S: fall back to plain prefix matching
+
+	// lastMarkdownPath is re-rendered through glamour on resize so `/md`
+	// output re-wraps to the new right-viewport width.
+	lastMarkdownPath string
+
+	// HID subsystem (darwin only, opt-in via --hid)
+	hidEnabled bool
+	hidHistory []int // last N touch event values, for the sparkline pane
+	hidSparkOn bool
+
+	// Declarative layout (gotop-style DSL); see layout.go.
+	widgets    map[string]Widget
+	widgetPane map[string]pane
+	layoutRows []layoutRow
+	layoutSpec string
 }
 
 // serverLogMsg is a custom message to send logs from the server to the TUI.
@@ -192,7 +218,8 @@ func initialModel(port string) model {
 			"API Log",
 			fmt.Sprintf("API server starting on port %s", port),
 		},
-		footerHelp: "Tab: Cycle Panes | Up/Down: Scroll | Ctrl+C: Quit",
+		footerHelp: "Tab: Cycle Panes | Up/Down: Scroll | Ctrl+R: History | Ctrl+P: Palette | Ctrl+C: Quit",
+		history:    loadHistory(),
 	}
 
 	m.loadCommands()
@@ -202,6 +229,16 @@ func initialModel(port string) model {
 // ======== BUBBLETEA LIFECYCL ========
 
 func (m *model) Init() tea.Cmd {
+	m.watchThemes()
+	m.registerWidgets()
+	spec := "default"
+	if saved, ok := loadLayoutConfig(); ok {
+		spec = saved
+	}
+	if err := m.applyLayout(spec); err != nil {
+		m.layoutRows, _ = parseLayout(layoutPresets["default"])
+		m.layoutSpec = "default"
+	}
 	return tea.Batch(textarea.Blink, m.startServerCmd())
 }
 
@@ -217,23 +254,71 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		// Always handle typing in the REPL if active
-		if m.activePane == replPane && msg.Type != tea.KeyEnter && msg.Type != tea.KeyTab {
+		paletteNav := m.palette != paletteOff && (msg.Type == tea.KeyUp || msg.Type == tea.KeyDown)
+		if m.activePane == replPane && !paletteNav && msg.Type != tea.KeyEnter && msg.Type != tea.KeyTab &&
+			msg.Type != tea.KeyCtrlR && msg.Type != tea.KeyCtrlP && msg.Type != tea.KeyCtrlS {
 			m.repl, cmd = m.repl.Update(msg)
 			m.updateSuggestions()
+			if m.palette != paletteOff {
+				m.updatePalette()
+			}
 			cmds = append(cmds, cmd)
 		}
 
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyCtrlC:
 			return m, tea.Sequence(m.shutdownServerCmd(), tea.Quit)
+		case tea.KeyEsc:
+			if m.palette != paletteOff {
+				m.palette = paletteOff
+			} else {
+				return m, tea.Sequence(m.shutdownServerCmd(), tea.Quit)
+			}
+		case tea.KeyCtrlR:
+			m.togglePalette(paletteHistory)
+		case tea.KeyCtrlP:
+			m.togglePalette(paletteCommands)
+		case tea.KeyCtrlS:
+			m.strict = !m.strict
+			m.updatePalette()
+		case tea.KeyUp:
+			if m.palette != paletteOff {
+				m.movePaletteSelection(-1)
+			} else if m.activePane != replPane {
+				switch m.activePane {
+				case leftPane:
+					m.leftVP, cmd = m.leftVP.Update(msg)
+					cmds = append(cmds, cmd)
+				case rightPane:
+					m.rightVP, cmd = m.rightVP.Update(msg)
+					cmds = append(cmds, cmd)
+				}
+			}
+		case tea.KeyDown:
+			if m.palette != paletteOff {
+				m.movePaletteSelection(1)
+			} else if m.activePane != replPane {
+				switch m.activePane {
+				case leftPane:
+					m.leftVP, cmd = m.leftVP.Update(msg)
+					cmds = append(cmds, cmd)
+				case rightPane:
+					m.rightVP, cmd = m.rightVP.Update(msg)
+					cmds = append(cmds, cmd)
+				}
+			}
 		case tea.KeyTab:
-			if m.activePane == replPane {
+			if m.palette != paletteOff {
+				m.applyPaletteSelection()
+			} else if m.activePane == replPane {
 				m.applySuggestion()
 			} else {
 				m.cyclePane()
 			}
 		case tea.KeyEnter:
-			if m.activePane == replPane {
+			if m.palette != paletteOff {
+				m.applyPaletteSelection()
+			} else if m.activePane == replPane {
 				m.handleInput()
 			}
 		default:
@@ -259,6 +344,15 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.leftContent = append(m.leftContent, msg.content)
 		m.leftVP.SetContent(strings.Join(m.leftContent, "\n"))
 		m.leftVP.GotoBottom()
+	case themeReloadedMsg:
+		m.leftContent = append(m.leftContent, "Theme reloaded: "+msg.name)
+		m.leftVP.SetContent(strings.Join(m.leftContent, "\n"))
+		m.leftVP.GotoBottom()
+		if m.themeName == msg.name {
+			m.currentTheme = m.themes[msg.name]
+		}
+	case hidEventMsg:
+		hidUpdate(m, msg)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -273,24 +367,9 @@ func (m *model) View() string {
 	header := s.header.Width(m.width).Render("Tubes")
 	footer := m.renderFooter(s)
 
-	leftWidth := m.width / 3
-	rightWidth := m.width - leftWidth
 	paneHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer) - 3 // 3 = repl height with borders
 
-	leftPaneStyle := s.paneBorder
-	rightPaneStyle := s.paneBorder
-
-	switch m.activePane {
-	case leftPane:
-		leftPaneStyle = s.paneBorderActive
-	case rightPane:
-		rightPaneStyle = s.paneBorderActive
-	}
-
-	left := leftPaneStyle.Width(leftWidth - 2).Height(paneHeight).Render(m.leftVP.View())
-	right := rightPaneStyle.Width(rightWidth - 2).Height(paneHeight).Render(m.rightVP.View())
-
-	content := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	content := m.renderLayout(m.width, paneHeight)
 	repl := s.repl.Width(m.width - 2).Render(m.repl.View())
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, content, repl, footer)
@@ -316,6 +395,13 @@ func (m *model) resizeLayout() {
 	m.rightVP.SetContent(strings.Join(m.rightContent, "\n"))
 
 	m.repl.SetWidth(m.width - 2)
+
+	if m.lastMarkdownPath != "" {
+		if out, err := m.renderMarkdown(m.lastMarkdownPath); err == nil {
+			m.rightContent = strings.Split(strings.TrimRight(out, "\n"), "\n")
+			m.rightVP.SetContent(out)
+		}
+	}
 }
 
 func (m *model) cyclePane() {
@@ -349,6 +435,9 @@ func (m *model) handleInput() {
 		m.leftContent = append(m.leftContent, "Error: Unknown command '"+cmdName+"'")
 	}
 
+	appendHistory(input)
+	m.history = append(m.history, input)
+
 	m.repl.Reset()
 	m.updateSuggestions()
 	m.leftVP.SetContent(strings.Join(m.leftContent, "\n"))
@@ -357,6 +446,29 @@ func (m *model) handleInput() {
 
 func (m *model) renderFooter(s Styles) string {
 	var footerText strings.Builder
+
+	if m.palette != paletteOff {
+		label := "History"
+		if m.palette == paletteCommands {
+			label = "Palette"
+		}
+		if m.strict {
+			label += " (strict)"
+		}
+		footerText.WriteString(label + ": ")
+		for i, match := range m.paletteMatches {
+			if i > 3 {
+				break
+			}
+			rendered := highlightMatch(match.text, match.indexes, func(str string) string { return s.completion.Render(str) })
+			if i == m.paletteSelected {
+				rendered = "[" + rendered + "]"
+			}
+			footerText.WriteString(rendered + "  ")
+		}
+		return s.footer.Width(m.width).Render(footerText.String())
+	}
+
 	footerText.WriteString(m.footerHelp)
 
 	if len(m.suggestions) > 0 && m.activePane == replPane {
@@ -398,11 +510,18 @@ func (m *model) loadCommands() {
 			},
 		},
 		"/theme": {
-			Name:        "/theme [ocean|neon]",
-			Description: "Changes the current color theme.",
+			Name:        "/theme [ocean|neon|reload]",
+			Description: "Changes the current color theme, or rescans themes/ with 'reload'.",
 			Executor: func(model *model, args []string) (string, error) {
 				if len(args) != 1 {
-					return "", errors.New("usage: /theme [ocean|neon]")
+					return "", errors.New("usage: /theme [ocean|neon|reload]")
+				}
+				if args[0] == "reload" {
+					added := model.rescanThemesDir()
+					if len(added) == 0 {
+						return "No new themes found.", nil
+					}
+					return fmt.Sprintf("Registered new themes: %s", strings.Join(added, ", ")), nil
 				}
 				themeName := args[0]
 				if theme, ok := model.themes[themeName]; ok {
@@ -431,6 +550,26 @@ func (m *model) loadCommands() {
 			Description: "Lists common fzf examples.",
 			Executor:    getFZFInfo,
 		},
+		"/md": {
+			Name:        "/md <path>",
+			Description: "Renders a markdown file into the right pane via glamour.",
+			Executor:    mdCommand,
+		},
+		"/lsys": {
+			Name:        "/lsys <axiom> <rule> <steps>",
+			Description: "Expands an L-system and draws it in the right pane.",
+			Executor:    lsysCommand,
+		},
+		"/hid": {
+			Name:        "/hid start|stop|filter <usagePage>",
+			Description: "Controls the optional HID multitouch scanner (darwin only).",
+			Executor:    hidCommand,
+		},
+		"/layout": {
+			Name:        "/layout <default|minimal|kitchensink|spec>",
+			Description: "Switches the pane layout DSL grid.",
+			Executor:    layoutCommand,
+		},
 	}
 }
 
@@ -456,6 +595,66 @@ func (m *model) applySuggestion() {
 	}
 }
 
+// togglePalette enters/exits Ctrl+R history search or Ctrl+P command palette
+// mode, re-entering the same mode closes it.
+func (m *model) togglePalette(mode paletteMode) {
+	if m.palette == mode {
+		m.palette = paletteOff
+		return
+	}
+	m.palette = mode
+	m.paletteSelected = 0
+	m.updatePalette()
+}
+
+// updatePalette re-ranks the palette's candidate list against the REPL's
+// current value.
+func (m *model) updatePalette() {
+	query := m.repl.Value()
+	var source []string
+	switch m.palette {
+	case paletteHistory:
+		source = m.history
+	case paletteCommands:
+		source = m.commandPaletteSource()
+	default:
+		return
+	}
+	m.paletteMatches = fuzzySearch(query, source, m.strict)
+	if m.paletteSelected >= len(m.paletteMatches) {
+		m.paletteSelected = 0
+	}
+}
+
+// movePaletteSelection shifts the highlighted palette entry by delta,
+// clamping to the ends of paletteMatches rather than wrapping.
+func (m *model) movePaletteSelection(delta int) {
+	if len(m.paletteMatches) == 0 {
+		return
+	}
+	m.paletteSelected += delta
+	if m.paletteSelected < 0 {
+		m.paletteSelected = 0
+	}
+	if m.paletteSelected >= len(m.paletteMatches) {
+		m.paletteSelected = len(m.paletteMatches) - 1
+	}
+}
+
+// applyPaletteSelection copies the highlighted palette entry into the REPL
+// and closes the palette.
+func (m *model) applyPaletteSelection() {
+	if m.paletteSelected >= len(m.paletteMatches) {
+		m.palette = paletteOff
+		return
+	}
+	choice := m.paletteMatches[m.paletteSelected].text
+	m.repl.SetValue(choice)
+	m.repl.SetCursor(len(choice))
+	m.palette = paletteOff
+	m.updateSuggestions()
+}
+
 func getFZFInfo(m *model, args []string) (string, error) {
 	var b strings.Builder
 	b.WriteString("FZF is a powerful command-line fuzzy finder.\n")
@@ -479,12 +678,6 @@ func (m *model) startServerCmd() tea.Cmd {
 			}
 		}
 
-		logMain := func(msg string) {
-			if m.program != nil {
-				m.program.Send(mainLogMsg{content: msg})
-			}
-		}
-
 		handler.HandleFunc("/api/list", func(w http.ResponseWriter, r *http.Request) {
 			logServer("GET /api/list")
 			w.Header().Set("Content-Type", "application/json")
@@ -506,17 +699,24 @@ func (m *model) startServerCmd() tea.Cmd {
 			}
 			var body struct {
 				Message string `json:"message"`
+				Session string `json:"session"` // session id, "all", or "" for the local TUI
 			}
 			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 				http.Error(w, "Invalid JSON", http.StatusBadRequest)
 				return
 			}
-			logServer(fmt.Sprintf("POST /log - msg: '%s'", body.Message))
-			logMain("[API] " + body.Message)
+			logServer(fmt.Sprintf("POST /log - msg: '%s' session: '%s'", body.Message, body.Session))
+			globalSessions.send(body.Session, mainLogMsg{content: "[API] " + body.Message})
 			w.WriteHeader(http.StatusOK)
 			json.NewEncoder(w).Encode(map[string]string{"status": "logged"})
 		})
 
+		handler.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+			logServer("GET /sessions")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string][]string{"sessions": globalSessions.ids()})
+		})
+
 		m.httpServer = &http.Server{
 			Addr:    ":" + m.apiPort,
 			Handler: handler,
@@ -551,8 +751,23 @@ func main() {
 	log.SetOutput(f)
 
 	port := flag.String("port", "8080", "Port for the API server")
+	sshAddr := flag.String("ssh", "", "Address to serve the TUI over SSH on (e.g. :2222); disabled when empty")
+	authorizedKeys := flag.String("ssh-authorized-keys", "", "Path to an authorized_keys file for SSH auth")
+	sshPassword := flag.String("ssh-password", "", "Optional password accepted by the SSH server")
+	hid := flag.Bool("hid", false, "Start the HID multitouch scanner pane at launch (darwin only)")
+	layout := flag.String("layout", "", "Pane layout preset (default|minimal|kitchensink) or a raw layout spec")
 	flag.Parse()
 
+	if *layout != "" {
+		saveLayoutConfig(*layout)
+	}
+
+	if *sshAddr != "" {
+		go func() {
+			startSSHServerCmd(*sshAddr, *authorizedKeys, *sshPassword, *port)()
+		}()
+	}
+
 	m := initialModel(*port)
 	p := tea.NewProgram(
 		&m,
@@ -560,6 +775,16 @@ func main() {
 		tea.WithMouseCellMotion(),
 	)
 	m.program = p // Give the model a reference to the program for sending messages
+	globalSessions.add(m.sessionID, p)
+	defer globalSessions.remove(m.sessionID)
+
+	if *hid {
+		if err := StartHIDManager(p); err != nil {
+			log.Printf("HID scanner disabled: %v", err)
+		} else {
+			m.hidEnabled = true
+		}
+	}
 
 	if _, err := p.Run(); err != nil {
 		log.Fatalf("Error running Tubes: %v", err)
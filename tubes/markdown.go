@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// glamourStyle derives a glamour ansi.StyleConfig from the currently
+// selected Theme, so `/theme neon` recolors rendered markdown headings too.
+func (m *model) glamourStyle() ansi.StyleConfig {
+	style := styles.DarkStyleConfig
+	heading := string(m.currentTheme.PaneBorderActive)
+	fg := string(m.currentTheme.HeaderFg)
+	style.Document.StylePrimitive.Color = &fg
+	style.H1.StylePrimitive.Color = &heading
+	style.H2.StylePrimitive.Color = &heading
+	style.H3.StylePrimitive.Color = &heading
+	return style
+}
+
+// renderMarkdown renders a markdown file through glamour, wrapped to the
+// right viewport's width.
+func (m *model) renderMarkdown(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStyles(m.glamourStyle()),
+		glamour.WithWordWrap(max(m.rightVP.Width, 20)),
+	)
+	if err != nil {
+		return "", err
+	}
+	return r.Render(string(data))
+}
+
+// mdCommand implements `/md <path>`: render a markdown file into the right
+// pane via glamour.
+func mdCommand(m *model, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: /md <path>")
+	}
+	out, err := m.renderMarkdown(args[0])
+	if err != nil {
+		return "", fmt.Errorf("render %s: %w", args[0], err)
+	}
+	m.lastMarkdownPath = args[0]
+	m.rightContent = strings.Split(strings.TrimRight(out, "\n"), "\n")
+	m.rightVP.SetContent(out)
+	return fmt.Sprintf("Rendered %s", args[0]), nil
+}
+
+// lsysCommand implements `/lsys <axiom> <rule> <steps>`: expand the L-system
+// and render it as a turtle-graphics ASCII drawing in the right viewport.
+func lsysCommand(m *model, args []string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf("usage: /lsys <axiom> <rule> <steps>")
+	}
+	steps, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid steps %q: %w", args[2], err)
+	}
+	instructions := ExpandLSystem(args[0], args[1], steps)
+	art := renderLSystemASCII(instructions, 25.7)
+	m.rightContent = strings.Split(art, "\n")
+	m.rightVP.SetContent(art)
+	return fmt.Sprintf("Rendered L-system (%d symbols after %d steps)", len(instructions), steps), nil
+}
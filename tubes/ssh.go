@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	bubbletea "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+)
+
+// sessionRegistry tracks one *tea.Program per connected SSH session (plus the
+// local program under the empty key) so the API server can target a single
+// session's TUI or fan a message out to all of them.
+type sessionRegistry struct {
+	mu       sync.Mutex
+	programs map[string]*tea.Program
+}
+
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{programs: make(map[string]*tea.Program)}
+}
+
+func (r *sessionRegistry) add(id string, p *tea.Program) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.programs[id] = p
+}
+
+func (r *sessionRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.programs, id)
+}
+
+func (r *sessionRegistry) ids() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.programs))
+	for id := range r.programs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// send delivers msg to the session identified by id, or to every connected
+// session when id is "" or "all".
+func (r *sessionRegistry) send(id string, msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == "" || id == "all" {
+		for _, p := range r.programs {
+			p.Send(msg)
+		}
+		return
+	}
+	if p, ok := r.programs[id]; ok {
+		p.Send(msg)
+	}
+}
+
+// globalSessions is shared between the local *tea.Program (registered under
+// the empty session id) and every SSH session's isolated program.
+var globalSessions = newSessionRegistry()
+
+// sshProgramHandler boots a fresh, isolated model per SSH connection, sized
+// to the requester's PTY, and registers the resulting *tea.Program so the
+// HTTP API can target it by session id.
+func sshProgramHandler(port string) func(s ssh.Session) *tea.Program {
+	return func(s ssh.Session) *tea.Program {
+		pty, _, active := s.Pty()
+		if !active {
+			return nil
+		}
+		id := s.RemoteAddr().String()
+		m := initialModel(port)
+		m.sessionID = id
+		m.width, m.height = pty.Window.Width, pty.Window.Height
+		m.leftContent = append(m.leftContent, fmt.Sprintf("Connected as SSH session %s", id))
+
+		p := tea.NewProgram(&m, tea.WithAltScreen(), tea.WithInput(s), tea.WithOutput(s))
+		m.program = p
+		globalSessions.add(id, p)
+
+		ctx := s.Context()
+		go func() {
+			<-ctx.Done()
+			globalSessions.remove(id)
+		}()
+
+		return p
+	}
+}
+
+// startSSHServerCmd boots a wish server that multiplexes the Tubes TUI, one
+// isolated instance per connection, alongside the existing HTTP API.
+func startSSHServerCmd(addr, authorizedKeys, password, port string) tea.Cmd {
+	return func() tea.Msg {
+		options := []ssh.Option{wish.WithAddress(addr)}
+		if authorizedKeys != "" {
+			options = append(options, wish.WithAuthorizedKeys(authorizedKeys))
+		}
+		if password != "" {
+			options = append(options, wish.WithPasswordAuth(func(ctx ssh.Context, pass string) bool {
+				return pass == password
+			}))
+		}
+		options = append(options, wish.WithMiddleware(
+			bubbletea.MiddlewareWithProgramHandler(sshProgramHandler(port), termenv.ANSI256),
+			logging.Middleware(),
+		))
+
+		srv, err := wish.NewServer(options...)
+		if err != nil {
+			log.Fatalf("failed to build SSH server: %v", err)
+		}
+
+		log.Printf("tubes: serving over SSH on %s", addr)
+		if err := srv.ListenAndServe(); err != nil {
+			log.Fatalf("SSH server failed: %v", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// themeReloadedMsg is sent into the tea.Program after a watched .theme file
+// is re-parsed, so Update can trigger a redraw.
+type themeReloadedMsg struct {
+	name string
+}
+
+// themesDir holds any additional *.theme files discovered alongside the two
+// built-in palettes.
+const themesDir = "themes"
+
+// watchThemes spawns a goroutine that watches dark_ocean.theme,
+// cyber_neon.theme, and themesDir for writes, re-parsing and swapping the
+// live theme on change.
+func (m *model) watchThemes() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		if m.program != nil {
+			m.program.Send(mainLogMsg{content: "theme watch disabled: " + err.Error()})
+		}
+		return
+	}
+
+	paths := map[string]string{
+		"dark_ocean.theme": "ocean",
+		"cyber_neon.theme": "neon",
+	}
+	for path := range paths {
+		watcher.Add(path)
+	}
+	watcher.Add(themesDir)
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				m.reloadThemeFile(event.Name, paths)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// reloadThemeFile re-parses a changed theme file and, if it is the active
+// theme, swaps it into the live model via a themeReloadedMsg.
+func (m *model) reloadThemeFile(path string, known map[string]string) {
+	if !strings.HasSuffix(path, ".theme") {
+		return
+	}
+	name, ok := known[filepath.Base(path)]
+	if !ok {
+		name = strings.TrimSuffix(filepath.Base(path), ".theme")
+	}
+
+	theme, err := loadTheme(path)
+	if err != nil {
+		if m.program != nil {
+			m.program.Send(mainLogMsg{content: "theme reload failed for " + path + ": " + err.Error()})
+		}
+		return
+	}
+
+	m.themes[name] = theme
+	if m.program != nil {
+		m.program.Send(themeReloadedMsg{name: name})
+	}
+}
+
+// rescanThemesDir registers any *.theme files in themesDir that are not
+// already known, for `/theme reload`.
+func (m *model) rescanThemesDir() (added []string) {
+	matches, err := filepath.Glob(filepath.Join(themesDir, "*.theme"))
+	if err != nil {
+		return nil
+	}
+	for _, path := range matches {
+		name := strings.TrimSuffix(filepath.Base(path), ".theme")
+		if _, ok := m.themes[name]; ok {
+			continue
+		}
+		theme, err := loadTheme(path)
+		if err != nil {
+			continue
+		}
+		m.themes[name] = theme
+		added = append(added, name)
+	}
+	return added
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// contentWidget adapts one of the model's existing string-slice panes
+// (leftContent, rightContent) into the Widget interface so the layout DSL
+// can place it anywhere in the grid.
+type contentWidget struct {
+	name    string
+	content func() []string
+}
+
+func (w contentWidget) Name() string          { return w.name }
+func (w contentWidget) Update(tea.Msg) tea.Cmd { return nil }
+
+func (w contentWidget) View(width, height int) string {
+	lines := w.content()
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	if width <= 0 {
+		return strings.Join(lines, "\n")
+	}
+	clip := lipgloss.NewStyle().MaxWidth(width)
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = clip.Render(l)
+	}
+	return strings.Join(out, "\n")
+}
+
+// hidWidget renders the live HID sparkline, independent of rightContent so
+// the "hid" and "api" panes can coexist in the kitchensink preset.
+type hidWidget struct{ m *model }
+
+func (w hidWidget) Name() string          { return "hid" }
+func (w hidWidget) Update(tea.Msg) tea.Cmd { return nil }
+func (w hidWidget) View(width, height int) string {
+	return renderHIDSparkline(w.m.hidHistory)
+}
+
+// registerWidgets populates m.widgets with the named panes the layout DSL
+// can reference: logs (left log pane), api (right log/markdown pane), hid
+// (touch sparkline), and md (an alias of api, for clarity in specs).
+func (m *model) registerWidgets() {
+	m.widgets = map[string]Widget{
+		"logs": contentWidget{name: "logs", content: func() []string { return m.leftContent }},
+		"api":  contentWidget{name: "api", content: func() []string { return m.rightContent }},
+		"md":   contentWidget{name: "md", content: func() []string { return m.rightContent }},
+		"hid":  hidWidget{m: m},
+	}
+	// widgetPane maps widget names back to the pane that owns Tab-focus over
+	// them, so renderLayout can border the cell matching m.activePane. "hid"
+	// has no owning pane: it isn't reachable by cyclePane.
+	m.widgetPane = map[string]pane{
+		"logs": leftPane,
+		"api":  rightPane,
+		"md":   rightPane,
+	}
+}